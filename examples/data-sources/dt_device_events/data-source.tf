@@ -0,0 +1,13 @@
+# Copyright (c) HashiCorp, Inc.
+
+data "dt_device_events" "freezer" {
+  provider    = disruptive-technologies
+  device      = "projects/your-project-id/devices/your-device-id"
+  event_types = ["temperature"]
+  limit       = 1
+}
+
+# Alert when the freezer gets 5 degrees warmer than its latest reading.
+output "freezer_threshold" {
+  value = jsondecode(data.dt_device_events.freezer.latest.data).temperature.value + 5
+}
@@ -0,0 +1,6 @@
+# Copyright (c) HashiCorp, Inc.
+
+provider "dt" {
+  url            = "https://api.disruptive-technologies.com"
+  token_endpoint = "https://identity.disruptive-technologies.com/oauth2/token"
+}
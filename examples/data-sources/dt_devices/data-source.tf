@@ -0,0 +1,13 @@
+# Copyright (c) HashiCorp, Inc.
+
+data "dt_devices" "freezers" {
+  provider      = disruptive-technologies
+  project       = "projects/your-project-id"
+  device_types  = ["temperature"]
+  label_filters = { room = "freezer" }
+}
+
+# The names can be used as the devices of a notification rule.
+output "freezer_names" {
+  value = [for device in data.dt_devices.freezers.devices : device.name]
+}
@@ -0,0 +1,34 @@
+# Copyright (c) HashiCorp, Inc.
+
+# Claim the sensors of a new building, and alert when any of them goes offline.
+resource "dt_device_claim" "building" {
+  project    = "projects/your-project-id"
+  kit_ids    = ["your-kit-id"]
+  device_ids = ["your-device-id"]
+}
+
+resource "dt_notification_rule" "building_offline" {
+  display_name = "Sensor offline"
+  project_id   = "your-project-id"
+  devices      = dt_device_claim.building.devices
+
+  trigger = {
+    field      = "connectionStatus"
+    connection = "SENSOR_OFFLINE"
+  }
+  escalation_levels = [
+    {
+      display_name = "Facility management"
+      actions = [
+        {
+          type = "EMAIL"
+          email_config = {
+            body       = "Sensor $name is offline"
+            recipients = ["facilities@example.com"]
+            subject    = "Sensor offline"
+          }
+        }
+      ]
+    }
+  ]
+}
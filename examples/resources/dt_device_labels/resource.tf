@@ -0,0 +1,11 @@
+# Copyright (c) HashiCorp, Inc.
+
+# Only the room and floor labels are managed, other labels on the sensor are
+# left as they are.
+resource "dt_device_labels" "freezer_sensor" {
+  name = "projects/your-project-id/devices/your-device-id"
+  labels = {
+    room  = "freezer"
+    floor = "2"
+  }
+}
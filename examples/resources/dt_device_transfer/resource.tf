@@ -0,0 +1,13 @@
+# Copyright (c) HashiCorp, Inc.
+
+# Move two sensors from the inventory project into the project of a site, and
+# back to the inventory when the site is decommissioned.
+resource "dt_device_transfer" "site_sensors" {
+  organization   = "organizations/your-organization-id"
+  project        = "projects/your-site-project-id"
+  return_project = "projects/your-inventory-project-id"
+  devices = [
+    "projects/your-inventory-project-id/devices/first-device-id",
+    "projects/your-inventory-project-id/devices/second-device-id",
+  ]
+}
@@ -0,0 +1,19 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_emulator" "freezer" {
+  display_name = "Emulated freezer"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "temperature"
+}
+
+# Publishes a temperature event when the emulator is created, and again
+# whenever the reading trigger changes.
+resource "dt_emulator_event" "freezer_temperature" {
+  emulator = dt_emulator.freezer.name
+  triggers = {
+    reading = "1"
+  }
+  temperature = {
+    value = -18.5
+  }
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheConfig controls the caches the client keeps of projects and
+// notification rules. The caches let the client read every resource in an
+// organization or project with a single list request, instead of one request
+// per resource.
+type CacheConfig struct {
+	// Disabled turns the caches off, so that every read is sent to the API.
+	Disabled bool
+	// TTL is how long a cached resource is used before it is read from the
+	// API again. Zero means that cached resources do not expire.
+	TTL time.Duration
+}
+
+// cache is a concurrency safe cache of resources by resource name.
+//
+// The client writes resources through the cache when it creates or updates
+// them, and evicts them when it deletes them, so the cache is coherent with
+// the changes made by the client itself. The TTL bounds how long changes made
+// outside of the client can go unnoticed.
+type cache[T any] struct {
+	disabled bool
+	ttl      time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]cacheEntry[T]
+
+	// The number of reads that were served from the cache, and that were not.
+	hits, misses atomic.Int64
+}
+
+type cacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// expired reports whether the entry has expired at now.
+func (e cacheEntry[T]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+func newCache[T any](cfg CacheConfig) *cache[T] {
+	return &cache[T]{
+		disabled: cfg.Disabled,
+		ttl:      cfg.TTL,
+		entries:  make(map[string]cacheEntry[T]),
+	}
+}
+
+// get returns the cached resource with the given name, if it is cached and
+// has not expired. Expired resources are evicted, so that the cache does not
+// grow for the life of the process.
+func (c *cache[T]) get(name string) (T, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[name]
+	c.mu.RUnlock()
+	if ok && entry.expired(time.Now()) {
+		c.evictExpired(name)
+		ok = false
+	}
+	if !ok {
+		c.misses.Add(1)
+		var zero T
+		return zero, false
+	}
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// evictExpired evicts the resource with the given name if it has expired. The
+// entry is checked again, as it may have been set since it was read.
+func (c *cache[T]) evictExpired(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[name]; ok && entry.expired(time.Now()) {
+		delete(c.entries, name)
+	}
+}
+
+// set caches the resource with the given name. It does nothing if the cache is disabled.
+func (c *cache[T]) set(name string, value T) {
+	if c.disabled {
+		return
+	}
+	entry := cacheEntry[T]{value: value}
+	if c.ttl > 0 {
+		entry.expires = time.Now().Add(c.ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[name] = entry
+}
+
+// usage returns the number of hits and misses of the cache.
+func (c *cache[T]) usage() CacheUsage {
+	u := CacheUsage{
+		Disabled: c.disabled,
+		Hits:     int(c.hits.Load()),
+		Misses:   int(c.misses.Load()),
+	}
+	if total := u.Hits + u.Misses; total > 0 {
+		u.HitRate = float64(u.Hits) / float64(total)
+	}
+	return u
+}
+
+// delete evicts the resource with the given name.
+func (c *cache[T]) delete(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, name)
+}
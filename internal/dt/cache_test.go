@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/dtfake"
+)
+
+// newCacheTestClient returns a fake server with a test organization and
+// project, and a client for it with the given cache configuration.
+func newCacheTestClient(t *testing.T, cfg dt.CacheConfig) (*dtfake.Server, *dt.Client) {
+	t.Helper()
+	server, client := newTestClient(t, nil, func(c *dt.Config) {
+		c.Cache = cfg
+	})
+	server.AddOrganization(testOrganization, "Test Org")
+	server.AddProject(dt.Project{Name: "projects/project", DisplayName: "project", Organization: testOrganization})
+	return server, client
+}
+
+func TestNotificationRuleCacheIsWrittenThrough(t *testing.T) {
+	t.Parallel()
+	_, client := newCacheTestClient(t, dt.CacheConfig{})
+	ctx := context.Background()
+
+	created, err := client.CreateNotificationRule(ctx, "projects/project", dt.NotificationRule{DisplayName: "before", Trigger: dt.Trigger{Field: "temperature"}})
+	if err != nil {
+		t.Fatalf("CreateNotificationRule() error = %v", err)
+	}
+	// Populate the cache.
+	if _, err := client.GetNotificationRule(ctx, created.Name); err != nil {
+		t.Fatalf("GetNotificationRule() error = %v", err)
+	}
+
+	created.DisplayName = "after"
+	if _, err := client.UpdateNotificationRule(ctx, created); err != nil {
+		t.Fatalf("UpdateNotificationRule() error = %v", err)
+	}
+	rule, err := client.GetNotificationRule(ctx, created.Name)
+	if err != nil {
+		t.Fatalf("GetNotificationRule() after update error = %v", err)
+	}
+	if rule.DisplayName != "after" {
+		t.Errorf("DisplayName after update = %q, want %q", rule.DisplayName, "after")
+	}
+
+	if err := client.DeleteNotificationRule(ctx, created.Name); err != nil {
+		t.Fatalf("DeleteNotificationRule() error = %v", err)
+	}
+	if _, err := client.GetNotificationRule(ctx, created.Name); !errors.Is(err, dt.ErrNotFound) {
+		t.Errorf("GetNotificationRule() after delete error = %v, want %v", err, dt.ErrNotFound)
+	}
+}
+
+func TestProjectCacheIsWrittenThrough(t *testing.T) {
+	t.Parallel()
+	_, client := newCacheTestClient(t, dt.CacheConfig{})
+	ctx := context.Background()
+
+	created, err := client.CreateProject(ctx, dt.Project{DisplayName: "before", Organization: testOrganization})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	// Populate the cache.
+	if _, err := client.GetProject(ctx, created.Name, testOrganization); err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+
+	if _, err := client.UpdateProject(ctx, dt.EditableProject{Name: created.Name, DisplayName: "after"}); err != nil {
+		t.Fatalf("UpdateProject() error = %v", err)
+	}
+	project, err := client.GetProject(ctx, created.Name, testOrganization)
+	if err != nil {
+		t.Fatalf("GetProject() after update error = %v", err)
+	}
+	if project.DisplayName != "after" {
+		t.Errorf("DisplayName after update = %q, want %q", project.DisplayName, "after")
+	}
+
+	if err := client.DeleteProject(ctx, created.Name); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+	if _, err := client.GetProject(ctx, created.Name, testOrganization); !errors.Is(err, dt.ErrNotFound) {
+		t.Errorf("GetProject() after delete error = %v, want %v", err, dt.ErrNotFound)
+	}
+}
+
+func TestProjectCacheSeesChangesMadeOutsideTheClient(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		cfg   dt.CacheConfig
+		wait  time.Duration
+		fresh bool
+	}{
+		{name: "cached", cfg: dt.CacheConfig{}, fresh: false},
+		{name: "disabled", cfg: dt.CacheConfig{Disabled: true}, fresh: true},
+		{name: "expired", cfg: dt.CacheConfig{TTL: 10 * time.Millisecond}, wait: 20 * time.Millisecond, fresh: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server, client := newCacheTestClient(t, tt.cfg)
+			ctx := context.Background()
+
+			if _, err := client.GetProject(ctx, "projects/project", testOrganization); err != nil {
+				t.Fatalf("GetProject() error = %v", err)
+			}
+			server.AddProject(dt.Project{Name: "projects/project", DisplayName: "renamed", Organization: testOrganization})
+			time.Sleep(tt.wait)
+
+			project, err := client.GetProject(ctx, "projects/project", testOrganization)
+			if err != nil {
+				t.Fatalf("GetProject() error = %v", err)
+			}
+			if got := project.DisplayName == "renamed"; got != tt.fresh {
+				t.Errorf("GetProject() returned display name %q, want fresh data %t", project.DisplayName, tt.fresh)
+			}
+		})
+	}
+}
+
+func TestNotificationRuleCacheCanBeDisabled(t *testing.T) {
+	t.Parallel()
+	server, client := newCacheTestClient(t, dt.CacheConfig{Disabled: true})
+	ctx := context.Background()
+
+	created, err := client.CreateNotificationRule(ctx, "projects/project", dt.NotificationRule{DisplayName: "before", Trigger: dt.Trigger{Field: "temperature"}})
+	if err != nil {
+		t.Fatalf("CreateNotificationRule() error = %v", err)
+	}
+	created.DisplayName = "renamed"
+	server.AddNotificationRule(created)
+
+	rule, err := client.GetNotificationRule(ctx, created.Name)
+	if err != nil {
+		t.Fatalf("GetNotificationRule() error = %v", err)
+	}
+	if rule.DisplayName != "renamed" {
+		t.Errorf("DisplayName = %q, want %q", rule.DisplayName, "renamed")
+	}
+
+	server.DeleteNotificationRule(created.Name)
+	if _, err := client.GetNotificationRule(ctx, created.Name); !errors.Is(err, dt.ErrNotFound) {
+		t.Errorf("GetNotificationRule() after delete error = %v, want %v", err, dt.ErrNotFound)
+	}
+}
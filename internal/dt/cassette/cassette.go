@@ -0,0 +1,276 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package cassette records the HTTP interactions of the dt and oidc clients
+// with the DT APIs to a file, a cassette, and replays them from it.
+//
+// Record a cassette once against the real APIs, commit it, and replay it in
+// CI to run the same requests offline and deterministically. The access
+// tokens of token responses, the JWT assertions of token requests and the
+// given secrets are scrubbed before anything is written to the cassette, and
+// request headers such as Authorization are not recorded at all.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/redact"
+)
+
+// Mode is whether a cassette records or replays interactions.
+type Mode string
+
+const (
+	// ModeOff sends requests as usual, without a cassette.
+	ModeOff Mode = ""
+	// ModeRecord sends requests to the API and records them.
+	ModeRecord Mode = "record"
+	// ModeReplay responds to requests with the recorded responses, without
+	// sending them to the API.
+	ModeReplay Mode = "replay"
+)
+
+// ParseMode parses a mode, such as the value of the DT_RECORD_MODE
+// environment variable. The empty string is ModeOff.
+func ParseMode(s string) (Mode, error) {
+	switch mode := Mode(s); mode {
+	case ModeOff, ModeRecord, ModeReplay:
+		return mode, nil
+	}
+	return ModeOff, fmt.Errorf("cassette: invalid mode %q, want %q or %q", s, ModeRecord, ModeReplay)
+}
+
+// Interaction is a request and the response to it.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Its headers are not recorded.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// file is the format of cassette files.
+type file struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// scrubPatterns match the credentials in request and response bodies. Only
+// the values are replaced, so that the bodies can still be decoded.
+var scrubPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{
+		regexp.MustCompile(`("(?:access_token|accessToken|refresh_token|refreshToken|id_token)"\s*:\s*)"(?:[^"\\]|\\.)*"`),
+		`${1}"` + redact.Mask + `"`,
+	},
+	{
+		regexp.MustCompile(`(assertion=)[^&\s"]+`),
+		`${1}` + redact.Mask,
+	},
+}
+
+// Cassette records interactions to a file, or replays them from it. It is
+// safe for concurrent use, and can be shared by the transports of several
+// clients, such as the ones of each provider instance in a test.
+type Cassette struct {
+	path    string
+	mode    Mode
+	secrets []string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	// replayed marks the interactions that have already been replayed, so
+	// that repeated requests get the responses in the order they were recorded.
+	replayed []bool
+}
+
+// Open opens the cassette at path. In ModeReplay the file must exist, and the
+// error wraps fs.ErrNotExist if it does not. In ModeRecord the file is
+// replaced when the first interaction is recorded. The secrets, such as the
+// service account key secret, are scrubbed from recorded interactions.
+func Open(path string, mode Mode, secrets ...string) (*Cassette, error) {
+	c := &Cassette{path: path, mode: mode}
+	for _, secret := range secrets {
+		if secret != "" {
+			c.secrets = append(c.secrets, secret)
+		}
+	}
+	if mode != ModeReplay {
+		return c, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read cassette: %w", err)
+	}
+	var f file
+	if err := json.Unmarshal(content, &f); err != nil {
+		return nil, fmt.Errorf("cassette: failed to decode cassette %s: %w", path, err)
+	}
+	c.interactions = f.Interactions
+	c.replayed = make([]bool, len(f.Interactions))
+	return c, nil
+}
+
+// Transport returns a round tripper that records the interactions of base, or
+// replays them without calling base, depending on the mode of the cassette.
+// A nil base means http.DefaultTransport.
+func (c *Cassette) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if c == nil || c.mode == ModeOff {
+		return base
+	}
+	return &transport{cassette: c, base: base}
+}
+
+type transport struct {
+	cassette *Cassette
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("cassette: failed to read request body: %w", err)
+		}
+	}
+	request := Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   t.cassette.scrub(string(body)),
+	}
+
+	if t.cassette.mode == ModeReplay {
+		return t.cassette.replay(req, request)
+	}
+
+	// Send the request with the body that was read.
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	response, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cassette: failed to read response body: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	header := response.Header.Clone()
+	header.Del("Set-Cookie")
+	// The scrubbed body can be shorter than the response.
+	header.Del("Content-Length")
+	if err := t.cassette.record(Interaction{
+		Request: request,
+		Response: Response{
+			StatusCode: response.StatusCode,
+			Header:     header,
+			Body:       t.cassette.scrub(string(responseBody)),
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// record appends an interaction to the cassette, and writes the cassette to
+// its file, so that nothing is lost if a test fails halfway.
+func (c *Cassette) record(interaction Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+
+	content, err := json.MarshalIndent(file{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cassette: failed to encode cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("cassette: failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("cassette: failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// replay returns the response of the first interaction that matches the
+// request and has not been replayed yet.
+func (c *Cassette) replay(req *http.Request, request Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, interaction := range c.interactions {
+		if c.replayed[i] || !matches(interaction.Request, request) {
+			continue
+		}
+		c.replayed[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no recorded response to %s %s in %s", request.Method, request.URL, c.path)
+}
+
+// matches reports whether two requests have the same method, URL and body.
+// JSON bodies are compared without insignificant whitespace.
+func matches(recorded, request Request) bool {
+	return recorded.Method == request.Method &&
+		recorded.URL == request.URL &&
+		compactJSON(recorded.Body) == compactJSON(request.Body)
+}
+
+// compactJSON returns s without insignificant whitespace if it is JSON, and
+// unchanged otherwise.
+func compactJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// scrub replaces the credentials and secrets in a body with redact.Mask.
+func (c *Cassette) scrub(body string) string {
+	for _, p := range scrubPatterns {
+		body = p.pattern.ReplaceAllString(body, p.replacement)
+	}
+	for _, secret := range c.secrets {
+		body = strings.ReplaceAll(body, secret, redact.Mask)
+	}
+	return body
+}
@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+
+package cassette_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/cassette"
+)
+
+func send(t *testing.T, client *http.Client, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, string(content)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/oauth2/token":
+			_, _ = w.Write([]byte(`{"access_token": "eyJhbGciOi.secret.token", "token_type": "bearer", "expires_in": 3600}`))
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		default:
+			// Repeated requests get different responses, such as a resource
+			// that is read before and after it is deleted.
+			if n > 3 {
+				w.WriteHeader(http.StatusNotFound)
+			}
+			_, _ = w.Write([]byte(`{"name": "projects/p"}`))
+		}
+	}))
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+
+	recorder, err := cassette.Open(path, cassette.ModeRecord, "key-secret")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	client := &http.Client{Transport: recorder.Transport(nil)}
+	send(t, client, http.MethodPost, server.URL+"/oauth2/token", "assertion=eyJhbGciOi.jwt.signature&grant_type=jwt-bearer")
+	send(t, client, http.MethodPost, server.URL+"/v2/projects", `{"displayName": "key-secret"}`)
+	send(t, client, http.MethodGet, server.URL+"/v2/projects/p", "")
+	send(t, client, http.MethodGet, server.URL+"/v2/projects/p", "")
+	server.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"eyJhbGciOi", "key-secret"} {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, content)
+		}
+	}
+
+	replayer, err := cassette.Open(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	client = &http.Client{Transport: replayer.Transport(nil)}
+	if status, body := send(t, client, http.MethodPost, server.URL+"/oauth2/token", "assertion=another.jwt.signature&grant_type=jwt-bearer"); status != http.StatusOK || !strings.Contains(body, `"access_token": "***"`) {
+		t.Errorf("token response = %d %s, want 200 with the access token scrubbed", status, body)
+	}
+	// The JSON body matches the recorded one regardless of whitespace.
+	if status, _ := send(t, client, http.MethodPost, server.URL+"/v2/projects", `{"displayName":"***"}`); status != http.StatusCreated {
+		t.Errorf("POST status = %d, want %d", status, http.StatusCreated)
+	}
+	if status, _ := send(t, client, http.MethodGet, server.URL+"/v2/projects/p", ""); status != http.StatusOK {
+		t.Errorf("first GET status = %d, want %d", status, http.StatusOK)
+	}
+	if status, _ := send(t, client, http.MethodGet, server.URL+"/v2/projects/p", ""); status != http.StatusNotFound {
+		t.Errorf("second GET status = %d, want %d", status, http.StatusNotFound)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/v2/projects/p", nil)
+	if _, err := client.Do(req); err == nil || !strings.Contains(err.Error(), "no recorded response to GET") {
+		t.Errorf("third GET error = %v, want no recorded response", err)
+	}
+}
+
+func TestReplayMatchesRequestBodies(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "test.json")
+	content := `{"interactions": [{
+		"request": {"method": "PATCH", "url": "https://api.example.com/v2/projects/p", "body": "{\"displayName\": \"a\"}"},
+		"response": {"status_code": 200, "body": "{}"}
+	}]}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	replayer, err := cassette.Open(path, cassette.ModeReplay)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPatch, "https://api.example.com/v2/projects/p", strings.NewReader(`{"displayName": "b"}`))
+	if _, err := replayer.Transport(nil).RoundTrip(req); err == nil {
+		t.Error("RoundTrip() with another body succeeded, want an error")
+	}
+}
+
+func TestOpenMissingCassette(t *testing.T) {
+	t.Parallel()
+	if _, err := cassette.Open(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeReplay); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open() error = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := cassette.Open(filepath.Join(t.TempDir(), "missing.json"), cassette.ModeRecord); err != nil {
+		t.Errorf("Open() in record mode error = %v", err)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+	for input, want := range map[string]cassette.Mode{
+		"":       cassette.ModeOff,
+		"record": cassette.ModeRecord,
+		"replay": cassette.ModeReplay,
+	} {
+		if got, err := cassette.ParseMode(input); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := cassette.ParseMode("playback"); err == nil {
+		t.Error(`ParseMode("playback") succeeded, want an error`)
+	}
+}
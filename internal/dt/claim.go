@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+)
+
+// Claim error codes returned for the kits and devices that could not be claimed.
+const (
+	ClaimErrorNotFound       = "NOT_FOUND"
+	ClaimErrorAlreadyClaimed = "ALREADY_CLAIMED"
+)
+
+// ClaimDevicesRequest is the kits and devices to claim into a project. Kits
+// are identified by the kit ID, and devices by the device ID printed on them.
+type ClaimDevicesRequest struct {
+	KitIDs    []string
+	DeviceIDs []string
+}
+
+type claimKit struct {
+	KitID string `json:"kitId"`
+}
+
+type claimDevice struct {
+	DeviceID string `json:"deviceId"`
+}
+
+type claimDevicesRequest struct {
+	Kits    []claimKit    `json:"kits"`
+	Devices []claimDevice `json:"devices"`
+}
+
+// ClaimedDevice is a device that was claimed, either by its device ID or as
+// part of a kit.
+type ClaimedDevice struct {
+	DeviceID      string `json:"deviceId"`
+	DeviceType    string `json:"deviceType"`
+	ProductNumber string `json:"productNumber"`
+	IsClaimed     bool   `json:"isClaimed"`
+}
+
+type claimDevicesResponse struct {
+	ClaimedDevices []ClaimedDevice `json:"claimedDevices"`
+	ClaimErrors    ClaimError      `json:"claimErrors"`
+}
+
+// KitClaimError is why a kit could not be claimed.
+type KitClaimError struct {
+	KitID   string `json:"kitId"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// DeviceClaimError is why a device could not be claimed.
+type DeviceClaimError struct {
+	DeviceID string `json:"deviceId"`
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+}
+
+// ClaimError is returned when a claim request succeeds, but some of its kits
+// or devices could not be claimed. It matches ErrNotFound or ErrAlreadyExists
+// if they all failed with the same code.
+type ClaimError struct {
+	Kits    []KitClaimError    `json:"kits"`
+	Devices []DeviceClaimError `json:"devices"`
+}
+
+func (e *ClaimError) Error() string {
+	messages := make([]string, 0, len(e.Kits)+len(e.Devices))
+	for _, kitErr := range e.Kits {
+		messages = append(messages, fmt.Sprintf("kit %s: %s %s", kitErr.KitID, kitErr.Code, kitErr.Message))
+	}
+	for _, deviceErr := range e.Devices {
+		messages = append(messages, fmt.Sprintf("device %s: %s %s", deviceErr.DeviceID, deviceErr.Code, deviceErr.Message))
+	}
+	return fmt.Sprintf("failed to claim %d kits and devices: %s", len(messages), strings.Join(messages, "; "))
+}
+
+// Is reports whether the kits and devices all failed with the code of the target.
+func (e *ClaimError) Is(target error) bool {
+	var code string
+	switch target {
+	case ErrNotFound:
+		code = ClaimErrorNotFound
+	case ErrAlreadyExists:
+		code = ClaimErrorAlreadyClaimed
+	default:
+		return false
+	}
+	if len(e.Kits) == 0 && len(e.Devices) == 0 {
+		return false
+	}
+	for _, kitErr := range e.Kits {
+		if kitErr.Code != code {
+			return false
+		}
+	}
+	for _, deviceErr := range e.Devices {
+		if deviceErr.Code != code {
+			return false
+		}
+	}
+	return true
+}
+
+// ClaimDevices claims kits and devices into a project, which moves them out
+// of the inventory of the seller and makes them show up in the project. The
+// devices that were claimed are returned, together with a *ClaimError for the
+// kits and devices that could not be claimed.
+func (c *Client) ClaimDevices(ctx context.Context, project string, req ClaimDevicesRequest) ([]ClaimedDevice, error) {
+	projectName, err := names.ParseProjectName(project)
+	if err != nil {
+		return nil, fmt.Errorf("dt: failed to parse resource name: %w", err)
+	}
+	request := claimDevicesRequest{
+		Kits:    make([]claimKit, 0, len(req.KitIDs)),
+		Devices: make([]claimDevice, 0, len(req.DeviceIDs)),
+	}
+	for _, kitID := range req.KitIDs {
+		request.Kits = append(request.Kits, claimKit{KitID: kitID})
+	}
+	for _, deviceID := range req.DeviceIDs {
+		request.Devices = append(request.Devices, claimDevice{DeviceID: deviceID})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("dt: failed to marshal claim devices request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/%s/devices:claim", strings.TrimSuffix(c.URL, "/"), projectName)
+	responseBody, err := c.DoRequest(ctx, http.MethodPost, url, body, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dt: failed to claim devices: %w", err)
+	}
+
+	var response claimDevicesResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("dt: failed to unmarshal claim devices response: %w", err)
+	}
+
+	// The device count of the cached project is out of date.
+	c.projectCache.delete(projectName.String())
+	if len(response.ClaimErrors.Kits) > 0 || len(response.ClaimErrors.Devices) > 0 {
+		return response.ClaimedDevices, &response.ClaimErrors
+	}
+	return response.ClaimedDevices, nil
+}
+
+// Kit is a kit of devices that are claimed together.
+type Kit struct {
+	KitID       string          `json:"kitId"`
+	DisplayName string          `json:"displayName"`
+	Devices     []ClaimedDevice `json:"devices"`
+}
+
+type claimInfoResponse struct {
+	Type string `json:"type"`
+	Kit  *Kit   `json:"kit"`
+}
+
+// GetKit returns a kit and its devices, whether or not they have been claimed.
+func (c *Client) GetKit(ctx context.Context, kitID string) (Kit, error) {
+	query := neturl.Values{"kitId": {kitID}}
+	url := fmt.Sprintf("%s/v2/claimInfo?%s", strings.TrimSuffix(c.URL, "/"), query.Encode())
+	responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, nil)
+	if err != nil {
+		return Kit{}, fmt.Errorf("dt: failed to get kit: %w", err)
+	}
+
+	var response claimInfoResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return Kit{}, fmt.Errorf("dt: failed to unmarshal claim info response: %w", err)
+	}
+	if response.Kit == nil {
+		return Kit{}, fmt.Errorf("dt: failed to get kit: %s is a %s, not a kit", kitID, response.Type)
+	}
+	return *response.Kit, nil
+}
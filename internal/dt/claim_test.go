@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func TestClaimDevices(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	server.AddClaimableDevice("c1", "temperature", "102058")
+	server.AddClaimableDevice("c2", "temperature", "102058")
+	server.AddClaimableDevice("c3", "proximity", "102071")
+	server.AddKit("kit1", "c1", "c2")
+	ctx := context.Background()
+
+	claimed, err := client.ClaimDevices(ctx, "projects/p1", dt.ClaimDevicesRequest{KitIDs: []string{"kit1"}, DeviceIDs: []string{"c3"}})
+	if err != nil {
+		t.Fatalf("ClaimDevices() error = %v", err)
+	}
+	var claimedIDs []string
+	for _, device := range claimed {
+		if !device.IsClaimed {
+			t.Errorf("device %s IsClaimed = false, want true", device.DeviceID)
+		}
+		claimedIDs = append(claimedIDs, device.DeviceID)
+	}
+	if want := []string{"c1", "c2", "c3"}; !slices.Equal(claimedIDs, want) {
+		t.Errorf("claimed devices = %v, want %v", claimedIDs, want)
+	}
+	got := listDeviceNames(t, client, dt.ListDevicesFilter{Project: "projects/p1", DeviceTypes: []string{"temperature"}})
+	if want := []string{"projects/p1/devices/c1", "projects/p1/devices/c2", "projects/p1/devices/d1", "projects/p1/devices/d2"}; !slices.Equal(got, want) {
+		t.Errorf("temperature sensors in projects/p1 = %v, want %v", got, want)
+	}
+
+	// Devices can only be claimed once.
+	_, err = client.ClaimDevices(ctx, "projects/p2", dt.ClaimDevicesRequest{KitIDs: []string{"kit1"}, DeviceIDs: []string{"c3"}})
+	if !errors.Is(err, dt.ErrAlreadyExists) {
+		t.Errorf("ClaimDevices() error = %v, want already exists", err)
+	}
+}
+
+func TestClaimDevicesReportsClaimErrors(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	server.AddClaimableDevice("c1", "temperature", "102058")
+
+	claimed, err := client.ClaimDevices(context.Background(), "projects/p1", dt.ClaimDevicesRequest{KitIDs: []string{"missing-kit"}, DeviceIDs: []string{"c1", "missing"}})
+	if len(claimed) != 1 || claimed[0].DeviceID != "c1" {
+		t.Errorf("ClaimDevices() = %v, want the device that could be claimed", claimed)
+	}
+	var claimErr *dt.ClaimError
+	if !errors.As(err, &claimErr) {
+		t.Fatalf("ClaimDevices() error = %v, want a *dt.ClaimError", err)
+	}
+	if len(claimErr.Kits) != 1 || len(claimErr.Devices) != 1 || claimErr.Devices[0].DeviceID != "missing" {
+		t.Errorf("ClaimError = %+v, want the missing kit and device", claimErr)
+	}
+	if !dt.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+	if want := "failed to claim 2 kits and devices: kit missing-kit: NOT_FOUND kit not found; device missing: NOT_FOUND device not found"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if _, err := client.ClaimDevices(context.Background(), "projects/missing", dt.ClaimDevicesRequest{DeviceIDs: []string{"c1"}}); !dt.IsNotFound(err) {
+		t.Errorf("ClaimDevices() in a missing project error = %v, want not found", err)
+	}
+}
+
+func TestGetKit(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	server.AddClaimableDevice("c1", "temperature", "102058")
+	server.AddClaimableDevice("c2", "temperature", "102058")
+	server.AddKit("kit1", "c1", "c2")
+	ctx := context.Background()
+	if _, err := client.ClaimDevices(ctx, "projects/p1", dt.ClaimDevicesRequest{DeviceIDs: []string{"c2"}}); err != nil {
+		t.Fatalf("ClaimDevices() error = %v", err)
+	}
+
+	kit, err := client.GetKit(ctx, "kit1")
+	if err != nil {
+		t.Fatalf("GetKit() error = %v", err)
+	}
+	want := []dt.ClaimedDevice{
+		{DeviceID: "c1", DeviceType: "temperature", ProductNumber: "102058"},
+		{DeviceID: "c2", DeviceType: "temperature", ProductNumber: "102058", IsClaimed: true},
+	}
+	if kit.KitID != "kit1" || !slices.Equal(kit.Devices, want) {
+		t.Errorf("GetKit() = %+v, want kit1 with devices %+v", kit, want)
+	}
+
+	if _, err := client.GetKit(ctx, "missing-kit"); !dt.IsNotFound(err) {
+		t.Errorf("GetKit() of a missing kit error = %v, want not found", err)
+	}
+}
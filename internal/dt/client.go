@@ -5,27 +5,40 @@ package dt
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"runtime"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/redact"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Client struct {
-	URL          string
-	EmulatorURL  string
-	httpClient   http.Client
-	oidc         *oidc.Client
-	retryAfter   *retryAfter
-	version      string
-	rulesCache   *rulesCache
-	projectCache *projectCache
+	URL           string
+	EmulatorURL   string
+	httpClient    http.Client
+	oidc          *oidc.Client
+	retryAfter    *retryAfter
+	retryPolicy   RetryPolicy
+	rateLimiter   *rateLimiter
+	pageSize      int
+	unsafeLogging bool
+	timeout       time.Duration
+	tracer        trace.Tracer
+	usage         *usage
+	version       string
+	rulesCache    *cache[NotificationRule]
+	projectCache  *cache[Project]
 }
 
 type retryAfter struct {
@@ -40,27 +53,72 @@ type Config struct {
 	URL         string
 	EmulatorURL string
 	Version     string
+	// Retry is the policy for retrying failed requests. Unset fields are
+	// replaced by the values of DefaultRetryPolicy.
+	Retry RetryPolicy
+	// RateLimit limits the rate of requests to the API and the number of
+	// requests in flight. Unset fields are replaced by the values of
+	// DefaultRateLimit.
+	RateLimit RateLimit
+	// PageSize is the number of items requested per page by list requests.
+	// It defaults to DefaultPageSize, and is capped at MaxPageSize.
+	PageSize int
+	// Cache controls the caches of projects and notification rules.
+	Cache CacheConfig
+	// UnsafeLogging disables the masking of secrets, such as webhook
+	// signature secrets, in the debug logs of requests. It is only meant for
+	// debugging locally.
+	UnsafeLogging bool
+	// HTTPClient is the HTTP client used for requests to the API and the
+	// token endpoint, see NewHTTPClient. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Timeout bounds the time DoRequest spends on a request, including the
+	// retries. Zero means no timeout.
+	Timeout time.Duration
+	// TracerProvider provides the tracer of the spans of requests to the API.
+	// It defaults to the global tracer provider.
+	TracerProvider trace.TracerProvider
 }
 
 func NewClient(cfg Config) *Client {
+	pageSize := cfg.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	pageSize = min(pageSize, MaxPageSize)
+
+	httpClient := http.DefaultClient
+	if cfg.HTTPClient != nil {
+		httpClient = cfg.HTTPClient
+		if cfg.Oidc.HTTPClient == nil {
+			cfg.Oidc.HTTPClient = cfg.HTTPClient
+		}
+	}
+
+	tracerProvider := cfg.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+
 	return &Client{
 		URL:         cfg.URL,
 		EmulatorURL: cfg.EmulatorURL,
-		httpClient:  *http.DefaultClient,
+		httpClient:  *httpClient,
 		oidc:        oidc.NewClient(cfg.Oidc),
 		retryAfter: &retryAfter{
 			t:  time.Now(),
 			mu: sync.RWMutex{},
 		},
-		version: cfg.Version,
-		rulesCache: &rulesCache{
-			notificationRules: make(map[string]NotificationRule),
-			mu:                sync.RWMutex{},
-		},
-		projectCache: &projectCache{
-			projects: make(map[string]Project),
-			mu:       sync.RWMutex{},
-		},
+		retryPolicy:   cfg.Retry.withDefaults(),
+		rateLimiter:   newRateLimiter(cfg.RateLimit),
+		pageSize:      pageSize,
+		unsafeLogging: cfg.UnsafeLogging,
+		timeout:       cfg.Timeout,
+		tracer:        tracerProvider.Tracer(tracerName),
+		usage:         newUsage(),
+		version:       cfg.Version,
+		rulesCache:    newCache[NotificationRule](cfg.Cache),
+		projectCache:  newCache[Project](cfg.Cache),
 	}
 }
 
@@ -69,15 +127,6 @@ func (c *Client) WithHttpClient(httpClient http.Client) *Client {
 	return c
 }
 
-type HTTPError struct {
-	StatusCode int
-	Body       string
-}
-
-func (e *HTTPError) Error() string {
-	return fmt.Sprintf("HTTP error: %d: %s", e.StatusCode, e.Body)
-}
-
 // time returns the retry after time.
 func (r *retryAfter) time() time.Time {
 	r.mu.RLock()
@@ -92,34 +141,134 @@ func (r *retryAfter) setTime(t time.Time) {
 	r.t = t
 }
 
+// DoRequest sends a request to the DT API and returns the response body.
+// Failed requests are retried according to the retry policy of the client.
+// Each call is traced by an OpenTelemetry span.
 func (c *Client) DoRequest(ctx context.Context, method, url string, requestBody []byte, params map[string]string) ([]byte, error) {
-	// Check if we need to wait for the retry after time
-	// before sending the request
-	time.Sleep(time.Until(c.retryAfter.time()))
+	ctx, span := c.startSpan(ctx, method, url)
+	body, err := c.doRequest(ctx, span, method, url, requestBody, params)
+	endSpan(span, err)
+	return body, err
+}
+
+func (c *Client) doRequest(ctx context.Context, span trace.Span, method, url string, requestBody []byte, params map[string]string) ([]byte, error) {
+	ctx = redact.Context(ctx, c.unsafeLogging)
+	for key, value := range params {
+		ctx = tflog.SetField(ctx, key, value)
+	}
+	ctx = tflog.SetField(ctx, "method", method)
+	ctx = tflog.SetField(ctx, "url", url)
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	reauthenticated := false
+	for attempt := 1; ; attempt++ {
+		// Check if we need to wait for the retry after time
+		// before sending the request
+		wait := time.Until(c.retryAfter.time())
+		if wait > 0 {
+			c.usage.wait(wait)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("dt: failed to send request: %w", err)
+		}
+
+		release, limited, err := c.rateLimiter.acquire(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("dt: failed to send request: %w", err)
+		}
+		if limited > 0 {
+			c.usage.limit(limited)
+		}
+
+		attemptCtx := tflog.SetField(ctx, "attempt", attempt)
+		bodyBytes, response, err := c.send(attemptCtx, method, url, requestBody, params)
+		release()
+		span.SetAttributes(attributeAttempts.Int(attempt))
+		if response != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+			c.rateLimiter.observe(response, time.Now())
+		}
+		if err == nil {
+			return bodyBytes, nil
+		}
+
+		var retry bool
+		var httpErr *HTTPError
+		var urlErr *neturl.Error
+		// The API rejects tokens that have expired or been revoked before the
+		// expiry we know of. Request a new token and send the request again,
+		// once, without counting it as an attempt.
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			reauthenticated = true
+			c.oidc.InvalidateToken(strings.TrimPrefix(response.Request.Header.Get("Authorization"), "Bearer "))
+			tflog.Debug(attemptCtx, "access token rejected by DT API, requesting a new token")
+			attempt--
+			continue
+		}
+		switch {
+		case errors.As(err, &httpErr):
+			retry = c.retryPolicy.retryStatus(method, url, httpErr.StatusCode)
+		case errors.As(err, &urlErr):
+			// The request failed without a response, e.g. on a connection reset.
+			retry = c.retryPolicy.retryError(ctx, method, url, err)
+		}
+		if !retry || attempt >= c.retryPolicy.MaxAttempts {
+			return nil, err
+		}
+
+		backoff := c.retryPolicy.backoff(attempt)
+		if response != nil && response.StatusCode == http.StatusTooManyRequests {
+			// The Retry-After header is shared by all requests, so that
+			// they all back off when the API is rate limiting us.
+			if retryAfter, ok := getRetryAfterTime(response); ok {
+				c.retryAfter.setTime(retryAfter)
+				backoff = 0
+			}
+		}
+		tflog.Debug(tflog.SetField(attemptCtx, "backoff", backoff.String()), "retrying request to DT API", map[string]any{"error": err.Error()})
+		span.AddEvent("retry", trace.WithAttributes(
+			attributeAttempts.Int(attempt),
+			attribute.String("backoff", backoff.String()),
+			attribute.String("error", err.Error()),
+		))
+		if err := sleep(ctx, backoff); err != nil {
+			return nil, fmt.Errorf("dt: failed to send request: %w", err)
+		}
+	}
+}
 
+// send sends a single request to the DT API. The response is nil if the
+// request failed before a response was received.
+func (c *Client) send(ctx context.Context, method, url string, requestBody []byte, params map[string]string) ([]byte, *http.Response, error) {
 	body := bytes.NewReader(requestBody)
 
 	request, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	query := request.URL.Query()
 	for key, value := range params {
 		query.Set(key, value)
-		ctx = tflog.SetField(ctx, key, value)
 	}
 	request.URL.RawQuery = query.Encode()
 
-	ctx = tflog.SetField(ctx, "method", method)
-	ctx = tflog.SetField(ctx, "url", url)
-
 	tflog.Debug(ctx, "sending request to DT API")
 
 	// Get an OIDC token and set it as a Bearer token in the request
 	token, err := c.oidc.GetToken(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("dt: failed to get OIDC token: %w", err)
+		// The token endpoint rejects invalid service account credentials.
+		var tokenErr *oidc.HTTPError
+		if errors.As(err, &tokenErr) && (tokenErr.StatusCode == http.StatusBadRequest || tokenErr.StatusCode == http.StatusUnauthorized) {
+			return nil, nil, fmt.Errorf("dt: failed to get OIDC token: %w: %w", ErrUnauthenticated, err)
+		}
+		return nil, nil, fmt.Errorf("dt: failed to get OIDC token: %w", err)
 	}
 	request.Header.Set("Authorization", "Bearer "+token.AccessToken)
 	request.Header.Set("Content-Type", "application/json")
@@ -127,60 +276,43 @@ func (c *Client) DoRequest(ctx context.Context, method, url string, requestBody
 
 	response, err := c.httpClient.Do(request)
 	if err != nil {
-		return nil, fmt.Errorf("dt: failed to send request: %w", err)
+		c.usage.request(method, url, 0)
+		return nil, nil, fmt.Errorf("dt: failed to send request: %w", err)
 	}
 	defer response.Body.Close()
+	c.usage.request(method, url, response.StatusCode)
 
 	bodyBytes, err := io.ReadAll(response.Body)
 	if err != nil {
-		return nil, fmt.Errorf("dt: failed to read response body: %w, status: %d", err, response.StatusCode)
+		return nil, response, fmt.Errorf("dt: failed to read response body: %w, status: %d", err, response.StatusCode)
 	}
 	ctx = tflog.SetField(ctx, "status_code", response.StatusCode)
 	ctx = tflog.SetField(ctx, "body", string(bodyBytes))
 	if response.StatusCode != http.StatusOK {
 		tflog.Debug(ctx, "received non-200 status code from DT API")
-		if response.StatusCode == http.StatusTooManyRequests {
-			c.retryAfter.setTime(getRetryAfterTime(response))
-			tflog.Debug(ctx, "received 429 status code from DT API, retrying request")
-
-			// Retry the request
-			return c.DoRequest(ctx, method, url, requestBody, params)
-		}
-		return nil, &HTTPError{
-			StatusCode: response.StatusCode,
-			Body:       string(bodyBytes),
-		}
+		return nil, response, newHTTPError(response.StatusCode, bodyBytes)
 	}
 
 	tflog.Debug(ctx, "received response from DT API")
 
-	return bodyBytes, nil
+	return bodyBytes, response, nil
 }
 
-// getRetryAfterTime gets the retry after time from a request by parsing the Retry-After header.
-func getRetryAfterTime(res *http.Response) time.Time {
+// getRetryAfterTime gets the retry after time from a response by parsing the
+// Retry-After header. It returns false if the header is missing or invalid.
+func getRetryAfterTime(res *http.Response) (time.Time, bool) {
 	retryAfter := res.Header.Get("Retry-After")
 	if retryAfter == "" {
-		return time.Now()
+		return time.Time{}, false
 	}
 	// Retry-After can be either a number of seconds or a date
 	retryAfterDuration, err := time.ParseDuration(retryAfter + "s")
 	if err != nil {
 		retryAfterTime, err := http.ParseTime(retryAfter)
 		if err != nil {
-			return time.Now()
+			return time.Time{}, false
 		}
-		return retryAfterTime
-	}
-	return time.Now().Add(retryAfterDuration)
-}
-
-// ParseResourceName is a helper function to parse the resource name projects/{projectID}/rules/{ruleID}
-// into projectID and notificationRuleID.
-func ParseResourceName(name string) (string, string, error) {
-	parts := strings.Split(name, "/")
-	if len(parts) != 4 {
-		return "", "", fmt.Errorf("dt: invalid resource name: %s", name)
+		return retryAfterTime, true
 	}
-	return parts[1], parts[3], nil
+	return time.Now().Add(retryAfterDuration), true
 }
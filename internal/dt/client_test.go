@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/dtfake"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+)
+
+// newTestClient returns a fake server and a client with tokens issued by it.
+// The client sends API requests to handler, or to the fake server if handler
+// is nil. configure, if not nil, changes the configuration of the client
+// before it is created.
+func newTestClient(t *testing.T, handler http.Handler, configure func(*dt.Config)) (*dtfake.Server, *dt.Client) {
+	t.Helper()
+	server := dtfake.NewServer()
+	t.Cleanup(server.Close)
+
+	cfg := dt.Config{
+		URL:         server.URL,
+		EmulatorURL: server.EmulatorURL,
+		Version:     "test",
+		Oidc: oidc.Config{
+			TokenEndpoint: server.TokenEndpoint,
+			ClientID:      server.KeyID,
+			ClientSecret:  server.KeySecret,
+			Email:         server.Email,
+		},
+	}
+	if handler != nil {
+		api := httptest.NewServer(handler)
+		t.Cleanup(api.Close)
+		cfg.URL = api.URL
+	}
+	if configure != nil {
+		configure(&cfg)
+	}
+	return server, dt.NewClient(cfg)
+}
+
+// statusCodesHandler returns a handler that responds with the given status
+// codes in order, and 200 OK once they are used up, and a counter of the
+// requests it has received.
+func statusCodesHandler(statusCodes ...int) (http.Handler, *atomic.Int32) {
+	var requests atomic.Int32
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := int(requests.Add(1)); n <= len(statusCodes) {
+			w.WriteHeader(statusCodes[n-1])
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}), &requests
+}
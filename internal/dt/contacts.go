@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 )
 
 type Contact struct {
@@ -118,11 +120,11 @@ type ContactGroup struct {
 }
 
 func (c *Client) GetContactGroup(name string) (ContactGroup, error) {
-	organizationID, scheduledExportID, err := ParseResourceName(name)
+	contactGroupName, err := names.ParseContactGroupName(name)
 	if err != nil {
 		return ContactGroup{}, fmt.Errorf("dt: failed to parse resource name: %w", err)
 	}
-	url := fmt.Sprintf("%s/v2/organizations/%s/contactGroups/%s", strings.TrimSuffix(c.URL, "/"), organizationID, scheduledExportID)
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), contactGroupName)
 
 	responseBody, err := c.DoRequest(context.Background(), http.MethodGet, url, nil, nil)
 	if err != nil {
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 )
 
 type DataConnector struct {
@@ -63,12 +65,12 @@ type AWSSQSConfig struct {
 
 // GetDatConnector retrieves a data connector by name.
 func (c *Client) GetDataConnector(ctx context.Context, dataConnector string) (DataConnector, error) {
-	projectID, dataConnectorID, err := parseDataConnectorResourceName(dataConnector)
+	dataConnectorName, err := names.ParseDataConnectorName(dataConnector)
 	if err != nil {
 		return DataConnector{}, err
 	}
 	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}/dataconnectors/{data_connector_id}
-	url := fmt.Sprintf("%s/v2/projects/%s/dataconnectors/%s", strings.TrimSuffix(c.URL, "/"), projectID, dataConnectorID)
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), dataConnectorName)
 
 	// Send a GET request to the API
 	responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, nil)
@@ -143,13 +145,13 @@ func (c *Client) CreateDataConnector(ctx context.Context, projectID string, data
 
 // UpdateDataConnector updates an existing data connector.
 func (c *Client) UpdateDataConnector(ctx context.Context, dc DataConnector) (DataConnector, error) {
-	projectID, dataConnectorID, err := parseDataConnectorResourceName(dc.Name)
+	dataConnectorName, err := names.ParseDataConnectorName(dc.Name)
 	if err != nil {
 		return DataConnector{}, err
 	}
 
 	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}/dataconnectors/{data_connector_id}
-	url := fmt.Sprintf("%s/v2/projects/%s/dataconnectors/%s", strings.TrimSuffix(c.URL, "/"), projectID, dataConnectorID)
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), dataConnectorName)
 	body, err := json.Marshal(dc)
 	if err != nil {
 		return DataConnector{}, err
@@ -173,13 +175,13 @@ func (c *Client) UpdateDataConnector(ctx context.Context, dc DataConnector) (Dat
 
 // DeleteDataConnector deletes a data connector.
 func (c *Client) DeleteDataConnector(ctx context.Context, dataConnector string) error {
-	projectID, dataConnectorID, err := parseDataConnectorResourceName(dataConnector)
+	dataConnectorName, err := names.ParseDataConnectorName(dataConnector)
 	if err != nil {
 		return err
 	}
 
 	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}/dataconnectors/{data_connector_id}
-	url := fmt.Sprintf("%s/v2/projects/%s/dataconnectors/%s", strings.TrimSuffix(c.URL, "/"), projectID, dataConnectorID)
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), dataConnectorName)
 
 	// Send a DELETE request to the API
 	_, err = c.DoRequest(ctx, http.MethodDelete, url, nil, nil)
@@ -191,20 +193,11 @@ func (c *Client) DeleteDataConnector(ctx context.Context, dataConnector string)
 }
 
 func (d DataConnector) ProjectID() string {
-	projectID, _, _ := parseDataConnectorResourceName(d.Name)
-	return projectID
+	dataConnectorName, _ := names.ParseDataConnectorName(d.Name)
+	return dataConnectorName.Project
 }
 
 func (d DataConnector) DataConnectorID() string {
-	_, dataConnectorID, _ := parseDataConnectorResourceName(d.Name)
-	return dataConnectorID
-}
-
-// parseDataConnectorResourceName parses a data connector resource name into projectID and dataConnectorID.
-func parseDataConnectorResourceName(name string) (projectID string, dataConnectorID string, err error) {
-	parts := strings.Split(name, "/")
-	if len(parts) != 4 {
-		return "", "", fmt.Errorf("invalid data connector name: %s", name)
-	}
-	return parts[1], parts[3], nil
+	dataConnectorName, _ := names.ParseDataConnectorName(d.Name)
+	return dataConnectorName.DataConnector
 }
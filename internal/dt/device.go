@@ -6,7 +6,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
+	"maps"
+	"net/http"
+	neturl "net/url"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 )
 
 type Device struct {
@@ -14,10 +22,213 @@ type Device struct {
 	Type          string            `json:"type"`
 	Labels        map[string]string `json:"labels"`
 	ProductNumber string            `json:"productNumber"`
+	// Reported is the last state reported by the device. It is nil for
+	// devices that have not reported anything yet.
+	Reported *Reported `json:"reported,omitempty"`
+}
+
+// Reported is the state reported by a device. Only the blocks of the events
+// that the type of the device sends are set, such as Temperature for
+// temperature sensors and ConnectionStatus for cloud connectors. Update times
+// are RFC 3339 timestamps.
+type Reported struct {
+	NetworkStatus      *NetworkStatus      `json:"networkStatus,omitempty"`
+	BatteryStatus      *BatteryStatus      `json:"batteryStatus,omitempty"`
+	Temperature        *Temperature        `json:"temperature,omitempty"`
+	Humidity           *Humidity           `json:"humidity,omitempty"`
+	ObjectPresent      *ObjectPresent      `json:"objectPresent,omitempty"`
+	ObjectPresentCount *ObjectPresentCount `json:"objectPresentCount,omitempty"`
+	Touch              *Touch              `json:"touch,omitempty"`
+	TouchCount         *TouchCount         `json:"touchCount,omitempty"`
+	WaterPresent       *WaterPresent       `json:"waterPresent,omitempty"`
+	CO2                *CO2                `json:"co2,omitempty"`
+	Pressure           *Pressure           `json:"pressure,omitempty"`
+	Motion             *Motion             `json:"motion,omitempty"`
+	DeskOccupancy      *DeskOccupancy      `json:"deskOccupancy,omitempty"`
+	Contact            *ContactState       `json:"contact,omitempty"`
+	ConnectionStatus   *ConnectionStatus   `json:"connectionStatus,omitempty"`
+	EthernetStatus     *EthernetStatus     `json:"ethernetStatus,omitempty"`
+	CellularStatus     *CellularStatus     `json:"cellularStatus,omitempty"`
+}
+
+// NetworkStatus is how well a sensor reached the cloud connectors that
+// received its last event.
+type NetworkStatus struct {
+	SignalStrength   int                      `json:"signalStrength"`
+	RSSI             int                      `json:"rssi"`
+	UpdateTime       string                   `json:"updateTime,omitempty"`
+	CloudConnectors  []NetworkStatusConnector `json:"cloudConnectors"`
+	TransmissionMode string                   `json:"transmissionMode"`
+}
+
+// NetworkStatusConnector is a cloud connector that received an event.
+type NetworkStatusConnector struct {
+	ID             string `json:"id"`
+	SignalStrength int    `json:"signalStrength"`
+	RSSI           int    `json:"rssi"`
+}
+
+type BatteryStatus struct {
+	Percentage int    `json:"percentage"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type Temperature struct {
+	Value      float64             `json:"value"`
+	UpdateTime string              `json:"updateTime,omitempty"`
+	Samples    []TemperatureSample `json:"samples,omitempty"`
+}
+
+type TemperatureSample struct {
+	Value      float64 `json:"value"`
+	SampleTime string  `json:"sampleTime"`
+}
+
+type Humidity struct {
+	Temperature      float64 `json:"temperature"`
+	RelativeHumidity float64 `json:"relativeHumidity"`
+	UpdateTime       string  `json:"updateTime,omitempty"`
+}
+
+// ObjectPresent is the state of a proximity sensor, PRESENT or NOT_PRESENT.
+type ObjectPresent struct {
+	State      string `json:"state"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type ObjectPresentCount struct {
+	Total      int    `json:"total"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type Touch struct {
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type TouchCount struct {
+	Total      int    `json:"total"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+// WaterPresent is the state of a water detector, PRESENT or NOT_PRESENT.
+type WaterPresent struct {
+	State      string `json:"state"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type CO2 struct {
+	PPM        int    `json:"ppm"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+type Pressure struct {
+	Pascal     float64 `json:"pascal"`
+	UpdateTime string  `json:"updateTime,omitempty"`
+}
+
+// Motion is the state of a motion sensor, MOTION_DETECTED or NO_MOTION_DETECTED.
+type Motion struct {
+	State      string `json:"state"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+// DeskOccupancy is the state of a desk occupancy sensor, OCCUPIED or NOT_OCCUPIED.
+type DeskOccupancy struct {
+	State      string `json:"state"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+// ContactState is the state of a contact sensor, OPEN or CLOSED.
+type ContactState struct {
+	State      string `json:"state"`
+	UpdateTime string `json:"updateTime,omitempty"`
+}
+
+// ConnectionStatus is how a cloud connector is connected to the cloud,
+// ETHERNET, CELLULAR or OFFLINE, and the connections it has available.
+type ConnectionStatus struct {
+	Connection string   `json:"connection"`
+	Available  []string `json:"available"`
+	UpdateTime string   `json:"updateTime,omitempty"`
+}
+
+type EthernetStatus struct {
+	MACAddress string   `json:"macAddress"`
+	IPAddress  string   `json:"ipAddress"`
+	Errors     []string `json:"errors"`
+	UpdateTime string   `json:"updateTime,omitempty"`
+}
+
+type CellularStatus struct {
+	SignalStrength int      `json:"signalStrength"`
+	Errors         []string `json:"errors"`
+	UpdateTime     string   `json:"updateTime,omitempty"`
+}
+
+type ListDevicesResponse struct {
+	Devices       []Device `json:"devices"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// ListDevicesFilter selects the devices returned by ListDevices. Unset
+// fields match every device.
+type ListDevicesFilter struct {
+	// Project is the name of the project to list the devices of. Empty or
+	// projects/- lists the devices of every project the service account has
+	// access to.
+	Project string
+	// DeviceIDs, DeviceTypes and ProductNumbers match devices with any of
+	// the IDs, types or product numbers.
+	DeviceIDs      []string
+	DeviceTypes    []string
+	ProductNumbers []string
+	// LabelFilters matches devices that have all of the labels. A filter with
+	// an empty value matches devices that have the label, with any value.
+	LabelFilters map[string]string
+	// Query matches devices by their ID or labels, like the search in DT Studio.
+	Query string
+	// OrderBy sorts the devices by a field, such as reported.temperature.value.
+	// Prefix it with - to sort in descending order.
+	OrderBy string
+}
+
+// query returns the query parameters of the filter. The filters that can be
+// repeated are not supported by DoRequest parameters, so they are encoded in
+// the URL.
+func (f ListDevicesFilter) query() neturl.Values {
+	query := neturl.Values{}
+	for _, id := range f.DeviceIDs {
+		query.Add("deviceIds", id)
+	}
+	for _, deviceType := range f.DeviceTypes {
+		query.Add("deviceTypes", deviceType)
+	}
+	for _, productNumber := range f.ProductNumbers {
+		query.Add("productNumbers", productNumber)
+	}
+	// Sort the label filters, so that the same filter always makes the same request.
+	for _, key := range slices.Sorted(maps.Keys(f.LabelFilters)) {
+		if value := f.LabelFilters[key]; value != "" {
+			query.Add("label_filters", key+"="+value)
+		} else {
+			query.Add("label_filters", key)
+		}
+	}
+	if f.Query != "" {
+		query.Set("query", f.Query)
+	}
+	if f.OrderBy != "" {
+		query.Set("orderBy", f.OrderBy)
+	}
+	return query
 }
 
 func (c *Client) GetDevice(ctx context.Context, deviceName string) (*Device, error) {
-	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), deviceName)
+	name, err := names.ParseDeviceName(deviceName)
+	if err != nil {
+		return nil, fmt.Errorf("dt: failed to parse resource name: %w", err)
+	}
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), name)
 	responseBody, err := c.DoRequest(ctx, "GET", url, nil, nil)
 	if err != nil {
 		return nil, fmt.Errorf("dt: failed to get device: %w", err)
@@ -30,3 +241,264 @@ func (c *Client) GetDevice(ctx context.Context, deviceName string) (*Device, err
 
 	return &device, nil
 }
+
+// ListDevices returns an iterator over the devices that match the filter.
+func (c *Client) ListDevices(ctx context.Context, filter ListDevicesFilter) iter.Seq2[Device, error] {
+	project := names.ProjectName{Project: "-"}
+	if filter.Project != "" {
+		var err error
+		project, err = names.ParseProjectName(filter.Project)
+		if err != nil {
+			return func(yield func(Device, error) bool) {
+				yield(Device{}, fmt.Errorf("dt: failed to parse resource name: %w", err))
+			}
+		}
+	}
+
+	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}/devices
+	url := fmt.Sprintf("%s/v2/%s/devices", strings.TrimSuffix(c.URL, "/"), project)
+	if query := filter.query(); len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+	return paginate(ctx, c, url, nil, func(body []byte) ([]Device, string, error) {
+		var devices ListDevicesResponse
+		if err := json.Unmarshal(body, &devices); err != nil {
+			return nil, "", fmt.Errorf("dt: failed to unmarshal devices: %w", err)
+		}
+		return devices.Devices, devices.NextPageToken, nil
+	})
+}
+
+// BatchUpdateDevicesRequest adds and removes labels of devices. Labels in
+// AddLabels are set to their value, whether the devices have them already or
+// not.
+type BatchUpdateDevicesRequest struct {
+	Devices      []string          `json:"devices"`
+	AddLabels    map[string]string `json:"addLabels,omitempty"`
+	RemoveLabels []string          `json:"removeLabels,omitempty"`
+}
+
+type batchUpdateDevicesResponse struct {
+	BatchErrors []DeviceError `json:"batchErrors"`
+}
+
+// BatchUpdateDevices updates the labels of devices in a project in a single
+// request. The devices that could not be updated are returned in a
+// *BatchError, and the others are updated anyway.
+func (c *Client) BatchUpdateDevices(ctx context.Context, project string, req BatchUpdateDevicesRequest) error {
+	projectName, err := names.ParseProjectName(project)
+	if err != nil {
+		return fmt.Errorf("dt: failed to parse resource name: %w", err)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("dt: failed to marshal batch update devices request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/%s/devices:batchUpdate", strings.TrimSuffix(c.URL, "/"), projectName)
+	responseBody, err := c.DoRequest(ctx, http.MethodPost, url, body, nil)
+	if err != nil {
+		return fmt.Errorf("dt: failed to update devices: %w", err)
+	}
+
+	var response batchUpdateDevicesResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return fmt.Errorf("dt: failed to unmarshal batch update devices response: %w", err)
+	}
+	if len(response.BatchErrors) > 0 {
+		return &BatchError{Operation: "update", Errors: response.BatchErrors}
+	}
+	return nil
+}
+
+// UpdateDeviceLabels sets and removes labels of a device. It does nothing if
+// there are no labels to set or remove.
+func (c *Client) UpdateDeviceLabels(ctx context.Context, device string, addLabels map[string]string, removeLabels []string) error {
+	if len(addLabels) == 0 && len(removeLabels) == 0 {
+		return nil
+	}
+	deviceName, err := names.ParseDeviceName(device)
+	if err != nil {
+		return fmt.Errorf("dt: failed to parse resource name: %w", err)
+	}
+	return c.BatchUpdateDevices(ctx, deviceName.ProjectName().String(), BatchUpdateDevicesRequest{
+		Devices:      []string{device},
+		AddLabels:    addLabels,
+		RemoveLabels: removeLabels,
+	})
+}
+
+type transferDevicesRequest struct {
+	Devices []string `json:"devices"`
+}
+
+type transferDevicesResponse struct {
+	TransferErrors []DeviceError `json:"transferErrors"`
+}
+
+// TransferDevices moves devices from their projects to the target project,
+// which can be in another organization. The service account needs to be a
+// project administrator of both projects. The devices that could not be
+// transferred are returned in a *BatchError, and the others are transferred
+// anyway.
+func (c *Client) TransferDevices(ctx context.Context, targetProject string, devices []string) error {
+	projectName, err := names.ParseProjectName(targetProject)
+	if err != nil {
+		return fmt.Errorf("dt: failed to parse resource name: %w", err)
+	}
+	body, err := json.Marshal(transferDevicesRequest{Devices: devices})
+	if err != nil {
+		return fmt.Errorf("dt: failed to marshal transfer devices request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/v2/%s/devices:transfer", strings.TrimSuffix(c.URL, "/"), projectName)
+	responseBody, err := c.DoRequest(ctx, http.MethodPost, url, body, nil)
+	if err != nil {
+		return fmt.Errorf("dt: failed to transfer devices: %w", err)
+	}
+
+	var response transferDevicesResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return fmt.Errorf("dt: failed to unmarshal transfer devices response: %w", err)
+	}
+	if len(response.TransferErrors) > 0 {
+		return &BatchError{Operation: "transfer", Errors: response.TransferErrors}
+	}
+
+	// The device counts of the cached projects are out of date.
+	c.projectCache.delete(projectName.String())
+	for _, device := range devices {
+		if deviceName, err := names.ParseDeviceName(device); err == nil {
+			c.projectCache.delete(deviceName.ProjectName().String())
+		}
+	}
+	return nil
+}
+
+// DeviceError is why a batch request failed for a single device.
+type DeviceError struct {
+	Device string      `json:"device"`
+	Status ErrorStatus `json:"status"`
+}
+
+// ErrorStatus is the status of a failed operation, with an HTTP status code.
+type ErrorStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// BatchError is returned when a batch request succeeds, but fails for some of
+// its devices. It matches the sentinel errors of the status codes of the
+// devices, like HTTPError, if they all failed with the same status code.
+type BatchError struct {
+	// Operation is the operation that failed, such as update or transfer.
+	Operation string
+	Errors    []DeviceError
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, deviceErr := range e.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %d %s", deviceErr.Device, deviceErr.Status.Code, deviceErr.Status.Message))
+	}
+	return fmt.Sprintf("failed to %s %d devices: %s", e.Operation, len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Is reports whether the devices all failed with the status code of the target.
+func (e *BatchError) Is(target error) bool {
+	if len(e.Errors) == 0 {
+		return false
+	}
+	for _, deviceErr := range e.Errors {
+		if !(&HTTPError{StatusCode: deviceErr.Status.Code}).Is(target) {
+			return false
+		}
+	}
+	return true
+}
+
+// EventTypes returns the event types of the blocks that are set, such as
+// temperature or networkStatus, sorted.
+func (r *Reported) EventTypes() []string {
+	if r == nil {
+		return nil
+	}
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil
+	}
+	var blocks map[string]json.RawMessage
+	if err := json.Unmarshal(body, &blocks); err != nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(blocks))
+}
+
+// LastUpdateTime returns the latest update time of the reported state, or
+// the zero time if the device has not reported anything.
+func (r *Reported) LastUpdateTime() time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+	var updateTimes []string
+	if r.NetworkStatus != nil {
+		updateTimes = append(updateTimes, r.NetworkStatus.UpdateTime)
+	}
+	if r.BatteryStatus != nil {
+		updateTimes = append(updateTimes, r.BatteryStatus.UpdateTime)
+	}
+	if r.Temperature != nil {
+		updateTimes = append(updateTimes, r.Temperature.UpdateTime)
+	}
+	if r.Humidity != nil {
+		updateTimes = append(updateTimes, r.Humidity.UpdateTime)
+	}
+	if r.ObjectPresent != nil {
+		updateTimes = append(updateTimes, r.ObjectPresent.UpdateTime)
+	}
+	if r.ObjectPresentCount != nil {
+		updateTimes = append(updateTimes, r.ObjectPresentCount.UpdateTime)
+	}
+	if r.Touch != nil {
+		updateTimes = append(updateTimes, r.Touch.UpdateTime)
+	}
+	if r.TouchCount != nil {
+		updateTimes = append(updateTimes, r.TouchCount.UpdateTime)
+	}
+	if r.WaterPresent != nil {
+		updateTimes = append(updateTimes, r.WaterPresent.UpdateTime)
+	}
+	if r.CO2 != nil {
+		updateTimes = append(updateTimes, r.CO2.UpdateTime)
+	}
+	if r.Pressure != nil {
+		updateTimes = append(updateTimes, r.Pressure.UpdateTime)
+	}
+	if r.Motion != nil {
+		updateTimes = append(updateTimes, r.Motion.UpdateTime)
+	}
+	if r.DeskOccupancy != nil {
+		updateTimes = append(updateTimes, r.DeskOccupancy.UpdateTime)
+	}
+	if r.Contact != nil {
+		updateTimes = append(updateTimes, r.Contact.UpdateTime)
+	}
+	if r.ConnectionStatus != nil {
+		updateTimes = append(updateTimes, r.ConnectionStatus.UpdateTime)
+	}
+	if r.EthernetStatus != nil {
+		updateTimes = append(updateTimes, r.EthernetStatus.UpdateTime)
+	}
+	if r.CellularStatus != nil {
+		updateTimes = append(updateTimes, r.CellularStatus.UpdateTime)
+	}
+
+	var last time.Time
+	for _, updateTime := range updateTimes {
+		// Blocks without a valid update time are ignored.
+		if t, err := time.Parse(time.RFC3339Nano, updateTime); err == nil && t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
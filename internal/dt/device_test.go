@@ -0,0 +1,376 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/dtfake"
+)
+
+// addTestDevices adds two projects to the server, with three devices in the
+// first one and one in the second.
+func addTestDevices(server *dtfake.Server) {
+	server.AddProject(dt.Project{Name: "projects/p1", Organization: testOrganization})
+	server.AddProject(dt.Project{Name: "projects/p2", Organization: testOrganization})
+	server.AddDevice(dt.Device{Name: "projects/p1/devices/d1", Type: "temperature", ProductNumber: "102058", Labels: map[string]string{"name": "Freezer", "room": "kitchen"}})
+	server.AddDevice(dt.Device{Name: "projects/p1/devices/d2", Type: "temperature", ProductNumber: "102150", Labels: map[string]string{"name": "Fridge", "room": "kitchen"}})
+	server.AddDevice(dt.Device{Name: "projects/p1/devices/d3", Type: "proximity", ProductNumber: "102071", Labels: map[string]string{"name": "Door", "room": ""}})
+	server.AddDevice(dt.Device{Name: "projects/p2/devices/d4", Type: "ccon", ProductNumber: "102091", Labels: map[string]string{"name": "Connector"}})
+}
+
+func listDeviceNames(t *testing.T, client *dt.Client, filter dt.ListDevicesFilter) []string {
+	t.Helper()
+	var got []string
+	for device, err := range client.ListDevices(context.Background(), filter) {
+		if err != nil {
+			t.Fatalf("ListDevices(%+v) error = %v", filter, err)
+		}
+		got = append(got, device.Name)
+	}
+	return got
+}
+
+func TestListDevices(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	server.SetPageSize(1)
+	addTestDevices(server)
+
+	tests := []struct {
+		name   string
+		filter dt.ListDevicesFilter
+		want   []string
+	}{
+		{
+			name:   "all projects",
+			filter: dt.ListDevicesFilter{},
+			want:   []string{"projects/p1/devices/d1", "projects/p1/devices/d2", "projects/p1/devices/d3", "projects/p2/devices/d4"},
+		},
+		{
+			name:   "project wildcard",
+			filter: dt.ListDevicesFilter{Project: "projects/-", DeviceTypes: []string{"ccon"}},
+			want:   []string{"projects/p2/devices/d4"},
+		},
+		{
+			name:   "project",
+			filter: dt.ListDevicesFilter{Project: "projects/p1"},
+			want:   []string{"projects/p1/devices/d1", "projects/p1/devices/d2", "projects/p1/devices/d3"},
+		},
+		{
+			name:   "device IDs",
+			filter: dt.ListDevicesFilter{DeviceIDs: []string{"d1", "d4"}},
+			want:   []string{"projects/p1/devices/d1", "projects/p2/devices/d4"},
+		},
+		{
+			name:   "device types",
+			filter: dt.ListDevicesFilter{Project: "projects/p1", DeviceTypes: []string{"proximity", "ccon"}},
+			want:   []string{"projects/p1/devices/d3"},
+		},
+		{
+			name:   "product numbers",
+			filter: dt.ListDevicesFilter{ProductNumbers: []string{"102150"}},
+			want:   []string{"projects/p1/devices/d2"},
+		},
+		{
+			name:   "label value",
+			filter: dt.ListDevicesFilter{LabelFilters: map[string]string{"room": "kitchen", "name": "Fridge"}},
+			want:   []string{"projects/p1/devices/d2"},
+		},
+		{
+			name:   "label key",
+			filter: dt.ListDevicesFilter{LabelFilters: map[string]string{"room": ""}},
+			want:   []string{"projects/p1/devices/d1", "projects/p1/devices/d2", "projects/p1/devices/d3"},
+		},
+		{
+			name:   "query",
+			filter: dt.ListDevicesFilter{Query: "Fr"},
+			want:   []string{"projects/p1/devices/d1", "projects/p1/devices/d2"},
+		},
+		{
+			name:   "no match",
+			filter: dt.ListDevicesFilter{DeviceTypes: []string{"humidity"}},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := listDeviceNames(t, client, tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("ListDevices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListDevicesSendsFilters(t *testing.T) {
+	t.Parallel()
+	var query map[string][]string
+	var path string
+	client := newRateLimitTestClient(t, dt.RateLimit{}, func(w http.ResponseWriter, r *http.Request) {
+		path, query = r.URL.Path, r.URL.Query()
+		_, _ = w.Write([]byte(`{"devices": [], "nextPageToken": ""}`))
+	})
+
+	filter := dt.ListDevicesFilter{
+		Project:        "projects/p1",
+		DeviceIDs:      []string{"d1", "d2"},
+		DeviceTypes:    []string{"temperature"},
+		ProductNumbers: []string{"102058"},
+		LabelFilters:   map[string]string{"room": "kitchen", "name": ""},
+		Query:          "fridge",
+		OrderBy:        "-reported.temperature.value",
+	}
+	listDeviceNames(t, client, filter)
+
+	if want := "/v2/projects/p1/devices"; path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	want := map[string][]string{
+		"deviceIds":      {"d1", "d2"},
+		"deviceTypes":    {"temperature"},
+		"productNumbers": {"102058"},
+		"label_filters":  {"name", "room=kitchen"},
+		"query":          {"fridge"},
+		"orderBy":        {"-reported.temperature.value"},
+		"pageSize":       {"100"},
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("query = %v, want %v", query, want)
+	}
+}
+
+func TestListDevicesInvalidProject(t *testing.T) {
+	t.Parallel()
+	_, client := newFakeTestClient(t, 0)
+
+	for _, err := range client.ListDevices(context.Background(), dt.ListDevicesFilter{Project: "p1"}) {
+		if err == nil {
+			t.Fatal("ListDevices() with a project ID succeeded, want an error")
+		}
+	}
+}
+
+func TestGetDeviceReportedState(t *testing.T) {
+	t.Parallel()
+	client := newRateLimitTestClient(t, dt.RateLimit{}, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"name": "projects/p1/devices/d1",
+			"type": "temperature",
+			"productNumber": "102058",
+			"labels": {"name": "Freezer"},
+			"reported": {
+				"networkStatus": {
+					"signalStrength": 45,
+					"rssi": -83,
+					"updateTime": "2025-05-16T08:21:21.076013Z",
+					"cloudConnectors": [{"id": "bdkjbo2v0000uk377c4g", "signalStrength": 45, "rssi": -83}],
+					"transmissionMode": "LOW_POWER_STANDARD_MODE"
+				},
+				"batteryStatus": {"percentage": 100, "updateTime": "2025-05-16T08:21:21.076013Z"},
+				"temperature": {
+					"value": -18.5,
+					"updateTime": "2025-05-16T08:21:21.076013Z",
+					"samples": [{"value": -18.5, "sampleTime": "2025-05-16T08:21:21.076013Z"}]
+				}
+			}
+		}`))
+	})
+
+	device, err := client.GetDevice(context.Background(), "projects/p1/devices/d1")
+	if err != nil {
+		t.Fatalf("GetDevice() error = %v", err)
+	}
+	reported := device.Reported
+	if reported == nil || reported.NetworkStatus == nil || reported.BatteryStatus == nil || reported.Temperature == nil {
+		t.Fatalf("GetDevice().Reported = %+v, want network status, battery status and temperature", reported)
+	}
+	if got := reported.NetworkStatus.CloudConnectors; len(got) != 1 || got[0].ID != "bdkjbo2v0000uk377c4g" || got[0].RSSI != -83 {
+		t.Errorf("NetworkStatus.CloudConnectors = %+v, want the cloud connector", got)
+	}
+	if got := reported.BatteryStatus.Percentage; got != 100 {
+		t.Errorf("BatteryStatus.Percentage = %d, want 100", got)
+	}
+	if got := reported.Temperature; got.Value != -18.5 || len(got.Samples) != 1 {
+		t.Errorf("Temperature = %+v, want -18.5 with one sample", got)
+	}
+	if reported.ObjectPresent != nil || reported.ConnectionStatus != nil {
+		t.Errorf("GetDevice().Reported = %+v, want only the blocks in the response", reported)
+	}
+}
+
+func TestGetDeviceFromFakeReportsState(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	server.AddProject(dt.Project{Name: "projects/p1", Organization: testOrganization})
+	server.AddDevice(dt.Device{
+		Name: "projects/p1/devices/d1",
+		Type: "ccon",
+		Reported: &dt.Reported{
+			ConnectionStatus: &dt.ConnectionStatus{Connection: "ETHERNET", Available: []string{"ETHERNET", "CELLULAR"}},
+		},
+	})
+
+	device, err := client.GetDevice(context.Background(), "projects/p1/devices/d1")
+	if err != nil {
+		t.Fatalf("GetDevice() error = %v", err)
+	}
+	if device.Reported == nil || device.Reported.ConnectionStatus == nil || device.Reported.ConnectionStatus.Connection != "ETHERNET" {
+		t.Errorf("GetDevice().Reported = %+v, want the connection status", device.Reported)
+	}
+}
+
+func TestBatchUpdateDevices(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	ctx := context.Background()
+
+	err := client.BatchUpdateDevices(ctx, "projects/p1", dt.BatchUpdateDevicesRequest{
+		Devices:      []string{"projects/p1/devices/d1", "projects/p1/devices/d2"},
+		AddLabels:    map[string]string{"floor": "1", "name": "Renamed"},
+		RemoveLabels: []string{"room"},
+	})
+	if err != nil {
+		t.Fatalf("BatchUpdateDevices() error = %v", err)
+	}
+	for _, name := range []string{"projects/p1/devices/d1", "projects/p1/devices/d2"} {
+		device, err := client.GetDevice(ctx, name)
+		if err != nil {
+			t.Fatalf("GetDevice() error = %v", err)
+		}
+		if want := map[string]string{"floor": "1", "name": "Renamed"}; !reflect.DeepEqual(device.Labels, want) {
+			t.Errorf("labels of %s = %v, want %v", name, device.Labels, want)
+		}
+	}
+}
+
+func TestBatchUpdateDevicesReportsDeviceErrors(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	ctx := context.Background()
+
+	err := client.BatchUpdateDevices(ctx, "projects/p1", dt.BatchUpdateDevicesRequest{
+		Devices:   []string{"projects/p1/devices/d1", "projects/p1/devices/missing"},
+		AddLabels: map[string]string{"floor": "1"},
+	})
+	var batchErr *dt.BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[0].Device != "projects/p1/devices/missing" {
+		t.Fatalf("BatchUpdateDevices() error = %v, want a batch error for the missing device", err)
+	}
+	if !dt.IsNotFound(err) {
+		t.Errorf("IsNotFound(%v) = false, want true", err)
+	}
+
+	// The devices that exist are updated anyway.
+	device, err := client.GetDevice(ctx, "projects/p1/devices/d1")
+	if err != nil {
+		t.Fatalf("GetDevice() error = %v", err)
+	}
+	if device.Labels["floor"] != "1" {
+		t.Errorf("labels = %v, want floor to be set", device.Labels)
+	}
+}
+
+func TestBatchErrorIs(t *testing.T) {
+	t.Parallel()
+	err := &dt.BatchError{Operation: "transfer", Errors: []dt.DeviceError{
+		{Device: "projects/p1/devices/d1", Status: dt.ErrorStatus{Code: http.StatusNotFound}},
+		{Device: "projects/p1/devices/d2", Status: dt.ErrorStatus{Code: http.StatusForbidden, Message: "no access"}},
+	}}
+	if errors.Is(err, dt.ErrNotFound) || errors.Is(err, dt.ErrPermissionDenied) {
+		t.Errorf("errors.Is(%v) = true, want false when the devices failed for different reasons", err)
+	}
+	if want := "failed to transfer 2 devices: projects/p1/devices/d1: 404 ; projects/p1/devices/d2: 403 no access"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestTransferDevices(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	ctx := context.Background()
+
+	// Cache the device counts of the projects before the transfer.
+	if _, err := client.GetProject(ctx, "projects/p2", testOrganization); err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+
+	if err := client.TransferDevices(ctx, "projects/p2", []string{"projects/p1/devices/d1", "projects/p1/devices/d2"}); err != nil {
+		t.Fatalf("TransferDevices() error = %v", err)
+	}
+
+	got := listDeviceNames(t, client, dt.ListDevicesFilter{Project: "projects/p2"})
+	if want := []string{"projects/p2/devices/d1", "projects/p2/devices/d2", "projects/p2/devices/d4"}; !slices.Equal(got, want) {
+		t.Errorf("devices in projects/p2 = %v, want %v", got, want)
+	}
+	project, err := client.GetProject(ctx, "projects/p2", testOrganization)
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project.SensorCount != 2 {
+		t.Errorf("SensorCount = %d, want the transferred devices to be counted", project.SensorCount)
+	}
+
+	err = client.TransferDevices(ctx, "projects/p2", []string{"projects/p1/devices/missing"})
+	if !dt.IsNotFound(err) {
+		t.Errorf("TransferDevices() error = %v, want not found", err)
+	}
+	if err := client.TransferDevices(ctx, "p2", nil); err == nil {
+		t.Error("TransferDevices() with a project ID succeeded, want an error")
+	}
+}
+
+func TestReportedLastUpdateTime(t *testing.T) {
+	t.Parallel()
+	reported := &dt.Reported{
+		NetworkStatus: &dt.NetworkStatus{UpdateTime: "2025-05-16T08:21:21.076013Z"},
+		BatteryStatus: &dt.BatteryStatus{UpdateTime: "2025-05-01T00:00:00Z"},
+		Temperature:   &dt.Temperature{UpdateTime: "2025-05-16T08:21:21.076013Z"},
+		Touch:         &dt.Touch{UpdateTime: "not a time"},
+	}
+	want := time.Date(2025, 5, 16, 8, 21, 21, 76013000, time.UTC)
+	if got := reported.LastUpdateTime(); !got.Equal(want) {
+		t.Errorf("LastUpdateTime() = %v, want %v", got, want)
+	}
+
+	var none *dt.Reported
+	if got := none.LastUpdateTime(); !got.IsZero() {
+		t.Errorf("LastUpdateTime() of no reported state = %v, want the zero time", got)
+	}
+}
+
+func TestUpdateDeviceLabels(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	ctx := context.Background()
+
+	if err := client.UpdateDeviceLabels(ctx, "projects/p1/devices/d1", map[string]string{"floor": "2"}, []string{"room"}); err != nil {
+		t.Fatalf("UpdateDeviceLabels() error = %v", err)
+	}
+	device, err := client.GetDevice(ctx, "projects/p1/devices/d1")
+	if err != nil {
+		t.Fatalf("GetDevice() error = %v", err)
+	}
+	if want := map[string]string{"name": "Freezer", "floor": "2"}; !reflect.DeepEqual(device.Labels, want) {
+		t.Errorf("labels = %v, want %v", device.Labels, want)
+	}
+
+	if err := client.UpdateDeviceLabels(ctx, "projects/p1/devices/missing", map[string]string{"floor": "2"}, nil); !dt.IsNotFound(err) {
+		t.Errorf("UpdateDeviceLabels() of a missing device error = %v, want not found", err)
+	}
+	// Nothing is sent when there is nothing to update.
+	if err := client.UpdateDeviceLabels(ctx, "invalid", nil, nil); err != nil {
+		t.Errorf("UpdateDeviceLabels() without labels error = %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"net/http"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+// claimableDevice is a device that has been sold, but not necessarily claimed
+// into a project yet.
+type claimableDevice struct {
+	deviceType    string
+	productNumber string
+	claimed       bool
+}
+
+type claimDevicesRequest struct {
+	Kits []struct {
+		KitID string `json:"kitId"`
+	} `json:"kits"`
+	Devices []struct {
+		DeviceID string `json:"deviceId"`
+	} `json:"devices"`
+}
+
+type claimDevicesResponse struct {
+	ClaimedDevices []dt.ClaimedDevice `json:"claimedDevices"`
+	ClaimErrors    dt.ClaimError      `json:"claimErrors"`
+}
+
+func (s *Server) registerClaimHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/projects/{project}/devices:claim", s.claimDevices)
+	mux.HandleFunc("GET /v2/claimInfo", s.getClaimInfo)
+}
+
+// AddClaimableDevice seeds a device that can be claimed into a project by its
+// device ID, or as part of a kit.
+func (s *Server) AddClaimableDevice(deviceID, deviceType, productNumber string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claimable[deviceID] = claimableDevice{deviceType: deviceType, productNumber: productNumber}
+}
+
+// AddKit seeds a kit of claimable devices. The devices must already have been
+// added with AddClaimableDevice.
+func (s *Server) AddKit(kitID string, deviceIDs ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.kits[kitID] = deviceIDs
+}
+
+func (s *Server) claimDevices(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+
+	var req claimDevicesRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[project]; !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+
+	response := claimDevicesResponse{
+		ClaimedDevices: []dt.ClaimedDevice{},
+		ClaimErrors:    dt.ClaimError{Kits: []dt.KitClaimError{}, Devices: []dt.DeviceClaimError{}},
+	}
+	for _, kit := range req.Kits {
+		deviceIDs, ok := s.kits[kit.KitID]
+		if !ok {
+			response.ClaimErrors.Kits = append(response.ClaimErrors.Kits, dt.KitClaimError{KitID: kit.KitID, Code: dt.ClaimErrorNotFound, Message: "kit not found"})
+			continue
+		}
+		// A kit is claimed as a whole, so it is already claimed if any of its
+		// devices are.
+		alreadyClaimed := false
+		for _, deviceID := range deviceIDs {
+			alreadyClaimed = alreadyClaimed || s.claimable[deviceID].claimed
+		}
+		if alreadyClaimed {
+			response.ClaimErrors.Kits = append(response.ClaimErrors.Kits, dt.KitClaimError{KitID: kit.KitID, Code: dt.ClaimErrorAlreadyClaimed, Message: "kit is already claimed"})
+			continue
+		}
+		for _, deviceID := range deviceIDs {
+			response.ClaimedDevices = append(response.ClaimedDevices, s.claim(project, deviceID))
+		}
+	}
+	for _, device := range req.Devices {
+		claimable, ok := s.claimable[device.DeviceID]
+		switch {
+		case !ok:
+			response.ClaimErrors.Devices = append(response.ClaimErrors.Devices, dt.DeviceClaimError{DeviceID: device.DeviceID, Code: dt.ClaimErrorNotFound, Message: "device not found"})
+		case claimable.claimed:
+			response.ClaimErrors.Devices = append(response.ClaimErrors.Devices, dt.DeviceClaimError{DeviceID: device.DeviceID, Code: dt.ClaimErrorAlreadyClaimed, Message: "device is already claimed"})
+		default:
+			response.ClaimedDevices = append(response.ClaimedDevices, s.claim(project, device.DeviceID))
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) getClaimInfo(w http.ResponseWriter, r *http.Request) {
+	kitID := r.URL.Query().Get("kitId")
+	if kitID == "" {
+		writeError(w, http.StatusBadRequest, "kitId is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deviceIDs, ok := s.kits[kitID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "kit not found: %s", kitID)
+		return
+	}
+	kit := dt.Kit{KitID: kitID, DisplayName: kitID, Devices: []dt.ClaimedDevice{}}
+	for _, deviceID := range deviceIDs {
+		claimable := s.claimable[deviceID]
+		kit.Devices = append(kit.Devices, dt.ClaimedDevice{
+			DeviceID:      deviceID,
+			DeviceType:    claimable.deviceType,
+			ProductNumber: claimable.productNumber,
+			IsClaimed:     claimable.claimed,
+		})
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Type string `json:"type"`
+		Kit  dt.Kit `json:"kit"`
+	}{Type: "KIT", Kit: kit})
+}
+
+// claim adds a claimable device to the project. The caller must hold s.mu.
+func (s *Server) claim(project, deviceID string) dt.ClaimedDevice {
+	claimable := s.claimable[deviceID]
+	claimable.claimed = true
+	s.claimable[deviceID] = claimable
+
+	device := dt.Device{
+		Name:          project + "/devices/" + deviceID,
+		Type:          claimable.deviceType,
+		ProductNumber: claimable.productNumber,
+		Labels:        map[string]string{},
+	}
+	s.devices[device.Name] = device
+	return dt.ClaimedDevice{
+		DeviceID:      deviceID,
+		DeviceType:    claimable.deviceType,
+		ProductNumber: claimable.productNumber,
+		IsClaimed:     true,
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"net/http"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func (s *Server) registerContactHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/organizations/{organization}/contactGroups", s.createContactGroup)
+	mux.HandleFunc("GET /v2/organizations/{organization}/contactGroups/{contactGroup}", s.getContactGroup)
+	mux.HandleFunc("PATCH /v2/organizations/{organization}/contactGroups/{contactGroup}", s.updateContactGroup)
+	mux.HandleFunc("DELETE /v2/organizations/{organization}/contactGroups/{contactGroup}", s.deleteContactGroup)
+
+	mux.HandleFunc("POST /v2/projects/{project}/contacts", s.createContact)
+	mux.HandleFunc("GET /v2/projects/{project}/contacts/{contact}", s.getContact)
+	mux.HandleFunc("PATCH /v2/projects/{project}/contacts/{contact}", s.updateContact)
+	mux.HandleFunc("DELETE /v2/projects/{project}/contacts/{contact}", s.deleteContact)
+}
+
+// AddContactGroup seeds a contact group. The organization in the group name must already exist.
+func (s *Server) AddContactGroup(group dt.ContactGroup) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contactGroups[group.Name] = group
+}
+
+// DeleteContactGroup removes a contact group, as if it was deleted outside of Terraform.
+func (s *Server) DeleteContactGroup(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.contactGroups, name)
+}
+
+// DeleteContact removes a contact, as if it was deleted outside of Terraform.
+func (s *Server) DeleteContact(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.contacts, name)
+}
+
+func contactGroupName(r *http.Request) string {
+	return "organizations/" + r.PathValue("organization") + "/contactGroups/" + r.PathValue("contactGroup")
+}
+
+func contactName(r *http.Request) string {
+	return "projects/" + r.PathValue("project") + "/contacts/" + r.PathValue("contact")
+}
+
+// contactGroupView returns the contact group as the API would return it. The caller must hold s.mu.
+func (s *Server) contactGroupView(group dt.ContactGroup) dt.ContactGroup {
+	group.ContactCount = 0
+	for _, contact := range s.contacts {
+		if contact.ContactGroup == group.Name {
+			group.ContactCount++
+		}
+	}
+	return group
+}
+
+func (s *Server) createContactGroup(w http.ResponseWriter, r *http.Request) {
+	organization := "organizations/" + r.PathValue("organization")
+
+	var req dt.ContactGroup
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.DisplayName == "" {
+		writeError(w, http.StatusBadRequest, "displayName is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.organizations[organization]; !ok {
+		writeError(w, http.StatusNotFound, "organization not found: %s", organization)
+		return
+	}
+	group := dt.ContactGroup{
+		Name:        organization + "/contactGroups/" + newID(),
+		DisplayName: req.DisplayName,
+		Description: req.Description,
+	}
+	s.contactGroups[group.Name] = group
+	writeJSON(w, http.StatusOK, s.contactGroupView(group))
+}
+
+func (s *Server) getContactGroup(w http.ResponseWriter, r *http.Request) {
+	name := contactGroupName(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.contactGroups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "contact group not found: %s", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.contactGroupView(group))
+}
+
+func (s *Server) updateContactGroup(w http.ResponseWriter, r *http.Request) {
+	name := contactGroupName(r)
+
+	var req dt.UpdateContactGroupRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group, ok := s.contactGroups[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "contact group not found: %s", name)
+		return
+	}
+	group.DisplayName = req.DisplayName
+	group.Description = req.Description
+	s.contactGroups[name] = group
+	writeJSON(w, http.StatusOK, s.contactGroupView(group))
+}
+
+func (s *Server) deleteContactGroup(w http.ResponseWriter, r *http.Request) {
+	name := contactGroupName(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.contactGroups[name]; !ok {
+		writeError(w, http.StatusNotFound, "contact group not found: %s", name)
+		return
+	}
+	delete(s.contactGroups, name)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) createContact(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+
+	var req dt.Contact
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.DisplayName == "" {
+		writeError(w, http.StatusBadRequest, "displayName is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[project]; !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+	if _, ok := s.contactGroups[req.ContactGroup]; !ok {
+		writeError(w, http.StatusBadRequest, "contact group not found: %s", req.ContactGroup)
+		return
+	}
+	contact := dt.Contact{
+		Name:         project + "/contacts/" + newID(),
+		ContactGroup: req.ContactGroup,
+		DisplayName:  req.DisplayName,
+		Email:        req.Email,
+		PhoneNumber:  req.PhoneNumber,
+	}
+	s.contacts[contact.Name] = contact
+	writeJSON(w, http.StatusOK, contact)
+}
+
+func (s *Server) getContact(w http.ResponseWriter, r *http.Request) {
+	name := contactName(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contact, ok := s.contacts[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "contact not found: %s", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, contact)
+}
+
+func (s *Server) updateContact(w http.ResponseWriter, r *http.Request) {
+	name := contactName(r)
+
+	var req dt.UpdateContactRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	contact, ok := s.contacts[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "contact not found: %s", name)
+		return
+	}
+	if _, ok := s.contactGroups[req.ContactGroup]; !ok {
+		writeError(w, http.StatusBadRequest, "contact group not found: %s", req.ContactGroup)
+		return
+	}
+	contact.ContactGroup = req.ContactGroup
+	contact.DisplayName = req.DisplayName
+	contact.Email = req.Email
+	contact.PhoneNumber = req.PhoneNumber
+	s.contacts[name] = contact
+	writeJSON(w, http.StatusOK, contact)
+}
+
+func (s *Server) deleteContact(w http.ResponseWriter, r *http.Request) {
+	name := contactName(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.contacts[name]; !ok {
+		writeError(w, http.StatusNotFound, "contact not found: %s", name)
+		return
+	}
+	delete(s.contacts, name)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
@@ -0,0 +1,125 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"net/http"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func (s *Server) registerDataConnectorHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/projects/{project}/dataconnectors", s.createDataConnector)
+	mux.HandleFunc("GET /v2/projects/{project}/dataconnectors/{dataConnector}", s.getDataConnector)
+	mux.HandleFunc("PATCH /v2/projects/{project}/dataconnectors/{dataConnector}", s.updateDataConnector)
+	mux.HandleFunc("DELETE /v2/projects/{project}/dataconnectors/{dataConnector}", s.deleteDataConnector)
+}
+
+// DeleteDataConnector removes a data connector, as if it was deleted outside of Terraform.
+func (s *Server) DeleteDataConnector(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dataConnectors, name)
+}
+
+func dataConnectorName(r *http.Request) string {
+	return "projects/" + r.PathValue("project") + "/dataconnectors/" + r.PathValue("dataConnector")
+}
+
+// normalizeDataConnector fills in the defaults the API applies to a data connector.
+func normalizeDataConnector(dc dt.DataConnector) dt.DataConnector {
+	if dc.Status == "" {
+		dc.Status = "ACTIVE"
+	}
+	if dc.Events == nil {
+		dc.Events = []string{}
+	}
+	if dc.Labels == nil {
+		dc.Labels = []string{}
+	}
+	return dc
+}
+
+func (s *Server) createDataConnector(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+
+	var req dt.CreateDataConnectorRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Type == "" {
+		writeError(w, http.StatusBadRequest, "type is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[project]; !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+	dc := normalizeDataConnector(dt.DataConnector{
+		Name:                  project + "/dataconnectors/" + newID(),
+		Type:                  req.Type,
+		DisplayName:           req.DisplayName,
+		Status:                req.Status,
+		Events:                req.Events,
+		Labels:                req.Labels,
+		HTTPConfig:            req.HTTPConfig,
+		AzureServiceBusConfig: req.AzureServiceBusConfig,
+		AzureEventHubConfig:   req.AzureEventHubConfig,
+		PubsubConfig:          req.PubsubConfig,
+		AWSSQSConfig:          req.AWSSQSConfig,
+	})
+	s.dataConnectors[dc.Name] = dc
+	writeJSON(w, http.StatusOK, dc)
+}
+
+func (s *Server) getDataConnector(w http.ResponseWriter, r *http.Request) {
+	name := dataConnectorName(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dc, ok := s.dataConnectors[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "data connector not found: %s", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, dc)
+}
+
+func (s *Server) updateDataConnector(w http.ResponseWriter, r *http.Request) {
+	name := dataConnectorName(r)
+
+	var req dt.DataConnector
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dc, ok := s.dataConnectors[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "data connector not found: %s", name)
+		return
+	}
+	// The type of a data connector cannot be changed.
+	req.Name = dc.Name
+	req.Type = dc.Type
+	dc = normalizeDataConnector(req)
+	s.dataConnectors[name] = dc
+	writeJSON(w, http.StatusOK, dc)
+}
+
+func (s *Server) deleteDataConnector(w http.ResponseWriter, r *http.Request) {
+	name := dataConnectorName(r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.dataConnectors[name]; !ok {
+		writeError(w, http.StatusNotFound, "data connector not found: %s", name)
+		return
+	}
+	delete(s.dataConnectors, name)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
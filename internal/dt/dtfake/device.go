@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func (s *Server) registerDeviceHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/projects/{project}/devices", s.listDevices)
+	mux.HandleFunc("GET /v2/projects/{project}/devices/{device}", s.getDevice)
+	mux.HandleFunc("POST /v2/projects/{project}/devices:batchUpdate", s.batchUpdateDevices)
+	mux.HandleFunc("POST /v2/projects/{project}/devices:transfer", s.transferDevices)
+}
+
+type listDevicesResponse struct {
+	Devices       []dt.Device `json:"devices"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+type batchUpdateDevicesResponse struct {
+	BatchErrors []dt.DeviceError `json:"batchErrors"`
+}
+
+type transferDevicesRequest struct {
+	Devices []string `json:"devices"`
+}
+
+type transferDevicesResponse struct {
+	TransferErrors []dt.DeviceError `json:"transferErrors"`
+}
+
+// AddDevice seeds a device, together with its reported state if it has one.
+// The project in the device name must already exist.
+func (s *Server) AddDevice(device dt.Device) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if device.Labels == nil {
+		device.Labels = map[string]string{}
+	}
+	s.devices[device.Name] = device
+}
+
+// DeleteDevice removes a device, as if it was deleted outside of Terraform.
+func (s *Server) DeleteDevice(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.devices, name)
+}
+
+// deviceProject returns the project resource name of a device resource name.
+func deviceProject(name string) string {
+	project, _, _ := strings.Cut(strings.TrimPrefix(name, "projects/"), "/")
+	return "projects/" + project
+}
+
+func (s *Server) getDevice(w http.ResponseWriter, r *http.Request) {
+	name := "projects/" + r.PathValue("project") + "/devices/" + r.PathValue("device")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "device not found: %s", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, deviceView(device))
+}
+
+// deviceView returns a copy of a device that does not share its labels with the server state.
+func deviceView(device dt.Device) dt.Device {
+	device.Labels = maps.Clone(device.Labels)
+	return device
+}
+
+// matchDevice reports whether a device matches the filters of a list devices
+// request. The orderBy parameter is not supported, and devices are always
+// sorted by name.
+func matchDevice(device dt.Device, query url.Values) bool {
+	_, id, _ := strings.Cut(strings.TrimPrefix(device.Name, deviceProject(device.Name)+"/"), "/")
+	if ids := query["deviceIds"]; len(ids) > 0 && !slices.Contains(ids, id) {
+		return false
+	}
+	if types := query["deviceTypes"]; len(types) > 0 && !slices.Contains(types, device.Type) {
+		return false
+	}
+	if productNumbers := query["productNumbers"]; len(productNumbers) > 0 && !slices.Contains(productNumbers, device.ProductNumber) {
+		return false
+	}
+	for _, filter := range query["label_filters"] {
+		key, value, hasValue := strings.Cut(filter, "=")
+		got, ok := device.Labels[key]
+		if !ok || (hasValue && got != value) {
+			return false
+		}
+	}
+	if search := query.Get("query"); search != "" {
+		found := strings.Contains(id, search)
+		for _, value := range device.Labels {
+			found = found || strings.Contains(value, search)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) listDevices(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	if _, ok := s.projects[project]; !ok && project != "projects/-" {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+	devices := sortedValues(s.devices, func(d dt.Device) bool {
+		return (project == "projects/-" || deviceProject(d.Name) == project) && matchDevice(d, query)
+	})
+	if project == "projects/-" {
+		// The API does not list the devices of all projects by resource
+		// name. List them by device ID, so that clients that rely on the
+		// order are noticed.
+		slices.SortStableFunc(devices, func(a, b dt.Device) int {
+			return strings.Compare(path.Base(a.Name), path.Base(b.Name))
+		})
+	}
+	for i := range devices {
+		devices[i] = deviceView(devices[i])
+	}
+	pageSize := s.listPageSize()
+	s.mu.Unlock()
+
+	page, nextPageToken, err := paginate(r, devices, pageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listDevicesResponse{Devices: page, NextPageToken: nextPageToken})
+}
+
+// deviceError returns the error of a device in a batch response.
+func deviceError(device string, status int, message string) dt.DeviceError {
+	return dt.DeviceError{Device: device, Status: dt.ErrorStatus{Code: status, Message: message}}
+}
+
+func (s *Server) batchUpdateDevices(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+
+	var req dt.BatchUpdateDevicesRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[project]; !ok && project != "projects/-" {
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+	response := batchUpdateDevicesResponse{BatchErrors: []dt.DeviceError{}}
+	for _, name := range req.Devices {
+		device, ok := s.devices[name]
+		if !ok || (project != "projects/-" && deviceProject(name) != project) {
+			response.BatchErrors = append(response.BatchErrors, deviceError(name, http.StatusNotFound, "device not found"))
+			continue
+		}
+		device.Labels = maps.Clone(device.Labels)
+		for _, key := range req.RemoveLabels {
+			delete(device.Labels, key)
+		}
+		maps.Copy(device.Labels, req.AddLabels)
+		s.devices[name] = device
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) transferDevices(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+
+	var req transferDevicesRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[project]; !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+	response := transferDevicesResponse{TransferErrors: []dt.DeviceError{}}
+	for _, name := range req.Devices {
+		device, ok := s.devices[name]
+		if !ok {
+			response.TransferErrors = append(response.TransferErrors, deviceError(name, http.StatusNotFound, "device not found"))
+			continue
+		}
+		delete(s.devices, name)
+		device.Name = project + strings.TrimPrefix(name, deviceProject(name))
+		s.devices[device.Name] = device
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+// emulatorIDPrefix is the prefix of the device ID of every emulated device.
+const emulatorIDPrefix = "emu"
+
+func (s *Server) registerEmulatorHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v2/projects/{project}/devices", s.createEmulator)
+	mux.HandleFunc("GET /v2/projects/{project}/devices/{device}", s.getEmulator)
+	mux.HandleFunc("PUT /v2/projects/{project}/devices/{device}", s.updateEmulator)
+	mux.HandleFunc("DELETE /v2/projects/{project}/devices/{device}", s.deleteEmulator)
+	// The device wildcard can not have a suffix, so it includes the :publish verb.
+	mux.HandleFunc("POST /v2/projects/{project}/devices/{device}", s.publishEmulatorEvent)
+}
+
+func emulatorToDevice(emulator dt.Emulator) dt.Device {
+	return dt.Device{
+		Name:   emulator.Name,
+		Type:   emulator.Type,
+		Labels: maps.Clone(emulator.Labels),
+	}
+}
+
+func deviceToEmulator(device dt.Device) dt.Emulator {
+	return dt.Emulator{
+		Name:   device.Name,
+		Type:   device.Type,
+		Labels: maps.Clone(device.Labels),
+	}
+}
+
+// emulatedDevice returns the emulated device addressed by the request. The caller must hold s.mu.
+func (s *Server) emulatedDevice(w http.ResponseWriter, r *http.Request) (dt.Device, bool) {
+	name := "projects/" + r.PathValue("project") + "/devices/" + r.PathValue("device")
+	device, ok := s.devices[name]
+	if !ok || !strings.HasPrefix(r.PathValue("device"), emulatorIDPrefix) {
+		writeError(w, http.StatusNotFound, "emulated device not found: %s", name)
+		return dt.Device{}, false
+	}
+	return device, true
+}
+
+func (s *Server) createEmulator(w http.ResponseWriter, r *http.Request) {
+	project := "projects/" + r.PathValue("project")
+
+	var req dt.Emulator
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Type == "" {
+		writeError(w, http.StatusBadRequest, "type is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[project]; !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", project)
+		return
+	}
+	req.Name = project + "/devices/" + emulatorIDPrefix + newID()
+	if req.Labels == nil {
+		req.Labels = map[string]string{}
+	}
+	s.devices[req.Name] = emulatorToDevice(req)
+	writeJSON(w, http.StatusOK, req)
+}
+
+func (s *Server) getEmulator(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.emulatedDevice(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, deviceToEmulator(device))
+}
+
+func (s *Server) updateEmulator(w http.ResponseWriter, r *http.Request) {
+	var req dt.Emulator
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.emulatedDevice(w, r)
+	if !ok {
+		return
+	}
+	if req.Type != "" {
+		device.Type = req.Type
+	}
+	device.Labels = maps.Clone(req.Labels)
+	if device.Labels == nil {
+		device.Labels = map[string]string{}
+	}
+	s.devices[device.Name] = device
+	writeJSON(w, http.StatusOK, deviceToEmulator(device))
+}
+
+func (s *Server) deleteEmulator(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.emulatedDevice(w, r)
+	if !ok {
+		return
+	}
+	delete(s.devices, device.Name)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) publishEmulatorEvent(w http.ResponseWriter, r *http.Request) {
+	deviceID, ok := strings.CutSuffix(r.PathValue("device"), ":publish")
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found: %s", r.URL.Path)
+		return
+	}
+	r.SetPathValue("device", deviceID)
+
+	var event dt.Reported
+	if !decodeBody(w, r, &event) {
+		return
+	}
+	eventTypes := event.EventTypes()
+	if len(eventTypes) != 1 {
+		writeError(w, http.StatusBadRequest, "exactly one event type is required, got %d", len(eventTypes))
+		return
+	}
+
+	// Set the update time of the event, and merge it into the reported state.
+	updateTime := time.Now().UTC().Format(time.RFC3339Nano)
+	var blocks map[string]map[string]any
+	body, _ := json.Marshal(event)
+	_ = json.Unmarshal(body, &blocks)
+	for _, block := range blocks {
+		if _, ok := block["updateTime"]; !ok {
+			block["updateTime"] = updateTime
+		}
+	}
+	data, _ := json.Marshal(blocks)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.emulatedDevice(w, r)
+	if !ok {
+		return
+	}
+	reported := map[string]json.RawMessage{}
+	if device.Reported != nil {
+		body, _ := json.Marshal(device.Reported)
+		_ = json.Unmarshal(body, &reported)
+	}
+	for eventType, block := range blocks {
+		reported[eventType], _ = json.Marshal(block)
+	}
+	body, _ = json.Marshal(reported)
+	device.Reported = &dt.Reported{}
+	_ = json.Unmarshal(body, device.Reported)
+	s.devices[device.Name] = device
+
+	s.addEvent(dt.Event{
+		TargetName: device.Name,
+		EventType:  eventTypes[0],
+		Data:       data,
+		Timestamp:  blocks[eventTypes[0]]["updateTime"].(string),
+	})
+	writeJSON(w, http.StatusOK, struct{}{})
+}
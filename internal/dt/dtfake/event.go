@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func (s *Server) registerEventHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/projects/{project}/devices/{device}/events", s.listEvents)
+}
+
+// AddEvent adds an event to the history of the device in its TargetName. The
+// event gets a random ID if it does not have one, and the current time as its
+// timestamp if it does not have one.
+func (s *Server) AddEvent(event dt.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addEvent(event)
+}
+
+// addEvent adds an event to the history of a device. The caller must hold s.mu.
+func (s *Server) addEvent(event dt.Event) {
+	if event.EventID == "" {
+		event.EventID = newID()
+	}
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	}
+	s.events[event.TargetName] = append(s.events[event.TargetName], event)
+}
+
+func (s *Server) listEvents(w http.ResponseWriter, r *http.Request) {
+	name := "projects/" + r.PathValue("project") + "/devices/" + r.PathValue("device")
+	query := r.URL.Query()
+
+	var startTime, endTime time.Time
+	for parameter, value := range map[string]*time.Time{"startTime": &startTime, "endTime": &endTime} {
+		if query.Has(parameter) {
+			t, err := time.Parse(time.RFC3339Nano, query.Get(parameter))
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid %s: %s", parameter, query.Get(parameter))
+				return
+			}
+			*value = t
+		}
+	}
+
+	s.mu.Lock()
+	if _, ok := s.devices[name]; !ok {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "device not found: %s", name)
+		return
+	}
+	events := []dt.Event{}
+	for _, event := range s.events[name] {
+		timestamp, _ := time.Parse(time.RFC3339Nano, event.Timestamp)
+		if eventTypes := query["eventTypes"]; len(eventTypes) > 0 && !slices.Contains(eventTypes, event.EventType) {
+			continue
+		}
+		if (!startTime.IsZero() && timestamp.Before(startTime)) || (!endTime.IsZero() && timestamp.After(endTime)) {
+			continue
+		}
+		events = append(events, event)
+	}
+	pageSize := s.listPageSize()
+	s.mu.Unlock()
+
+	// The newest events are listed first.
+	slices.SortStableFunc(events, func(a, b dt.Event) int {
+		aTime, _ := time.Parse(time.RFC3339Nano, a.Timestamp)
+		bTime, _ := time.Parse(time.RFC3339Nano, b.Timestamp)
+		return bTime.Compare(aTime)
+	})
+	page, nextPageToken, err := paginate(r, events, pageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dt.ListEventsResponse{Events: page, NextPageToken: nextPageToken})
+}
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+// validProjectRoles are the roles that can be granted on a project.
+var validProjectRoles = []string{"roles/project.user", "roles/project.admin"}
+
+func (s *Server) registerMemberHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/projects/{project}/members", s.listProjectMembers)
+	mux.HandleFunc("POST /v2/projects/-/members:batchCreate", s.batchCreateProjectMembers)
+	mux.HandleFunc("POST /v2/projects/-/members:batchDelete", s.batchDeleteProjectMembers)
+	mux.HandleFunc("PATCH /v2/projects/{project}/members/{member}", s.updateProjectMember)
+}
+
+// DeleteMember removes a project membership, as if it was deleted outside of Terraform.
+func (s *Server) DeleteMember(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.members, name)
+}
+
+// memberID returns the member ID of an email address, allocating one for
+// unknown users. Service accounts use the ID in their email address. The
+// caller must hold s.mu.
+func (s *Server) memberID(email string) string {
+	if id, ok := s.memberIDs[email]; ok {
+		return id
+	}
+	id := strconv.Itoa(100000 + len(s.memberIDs))
+	if local, domain, ok := strings.Cut(email, "@"); ok && strings.Contains(domain, ".serviceaccount.") {
+		id = local
+	}
+	s.memberIDs[email] = id
+	return id
+}
+
+func accountType(email string) string {
+	if strings.Contains(email, ".serviceaccount.") {
+		return "SERVICE_ACCOUNT"
+	}
+	return "USER"
+}
+
+func validRoles(roles []string) bool {
+	return len(roles) == 1 && slices.Contains(validProjectRoles, roles[0])
+}
+
+func (s *Server) listProjectMembers(w http.ResponseWriter, r *http.Request) {
+	project := r.PathValue("project")
+	organization := r.URL.Query().Get("organization")
+	memberID := r.URL.Query().Get("memberId")
+
+	s.mu.Lock()
+	if project == "-" && organization == "" {
+		s.mu.Unlock()
+		writeError(w, http.StatusBadRequest, "organization is required when listing members across projects")
+		return
+	}
+	members := sortedValues(s.members, func(m dt.Membership) bool {
+		memberProject := deviceProject(m.Name)
+		if project != "-" && memberProject != "projects/"+project {
+			return false
+		}
+		if organization != "" && s.projects[memberProject].Organization != organization {
+			return false
+		}
+		return memberID == "" || lastSegment(m.Name) == memberID
+	})
+	pageSize := s.listPageSize()
+	s.mu.Unlock()
+
+	page, nextPageToken, err := paginate(r, members, pageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, dt.ListProjectMembersResponse{Members: page, NextPageToken: nextPageToken})
+}
+
+func (s *Server) batchCreateProjectMembers(w http.ResponseWriter, r *http.Request) {
+	var req dt.BatchCreateProjectsMembersRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, member := range req.Members {
+		if _, ok := s.projects[member.Project]; !ok {
+			writeError(w, http.StatusNotFound, "project not found: %s", member.Project)
+			return
+		}
+		if !validRoles(member.Roles) {
+			writeError(w, http.StatusBadRequest, "invalid roles: %v", member.Roles)
+			return
+		}
+		name := member.Project + "/members/" + s.memberID(member.Email)
+		if _, ok := s.members[name]; ok {
+			writeError(w, http.StatusConflict, "member already exists: %s", name)
+			return
+		}
+	}
+
+	created := make([]dt.Membership, 0, len(req.Members))
+	for _, member := range req.Members {
+		local, _, _ := strings.Cut(member.Email, "@")
+		membership := dt.Membership{
+			Name:        member.Project + "/members/" + s.memberID(member.Email),
+			DisplayName: local,
+			Roles:       slices.Clone(member.Roles),
+			Email:       member.Email,
+			AccountType: accountType(member.Email),
+		}
+		s.members[membership.Name] = membership
+		created = append(created, membership)
+	}
+	writeJSON(w, http.StatusOK, dt.MembershipResponse{Memberships: created})
+}
+
+func (s *Server) updateProjectMember(w http.ResponseWriter, r *http.Request) {
+	name := "projects/" + r.PathValue("project") + "/members/" + r.PathValue("member")
+
+	var req dt.UpdateProjectMemberRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if !validRoles(req.Roles) {
+		writeError(w, http.StatusBadRequest, "invalid roles: %v", req.Roles)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	membership, ok := s.members[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "member not found: %s", name)
+		return
+	}
+	membership.Roles = slices.Clone(req.Roles)
+	s.members[name] = membership
+	writeJSON(w, http.StatusOK, membership)
+}
+
+func (s *Server) batchDeleteProjectMembers(w http.ResponseWriter, r *http.Request) {
+	var req dt.BatchDeleteProjectMembersRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range req.Names {
+		if _, ok := s.members[name]; !ok {
+			writeError(w, http.StatusNotFound, "member not found: %s", name)
+			return
+		}
+	}
+	for _, name := range req.Names {
+		delete(s.members, name)
+	}
+	writeJSON(w, http.StatusOK, struct{}{})
+}
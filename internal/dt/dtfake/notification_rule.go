@@ -0,0 +1,193 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+type listNotificationRulesResponse struct {
+	Rules         []dt.NotificationRule `json:"rules"`
+	NextPageToken string                `json:"nextPageToken"`
+}
+
+func (s *Server) registerNotificationRuleHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2alpha/{parentType}/{parent}/rules", s.listNotificationRules)
+	mux.HandleFunc("POST /v2alpha/{parentType}/{parent}/rules", s.createNotificationRule)
+	mux.HandleFunc("GET /v2alpha/{parentType}/{parent}/rules/{rule}", s.getNotificationRule)
+	mux.HandleFunc("PUT /v2alpha/{parentType}/{parent}/rules/{rule}", s.updateNotificationRule)
+	mux.HandleFunc("DELETE /v2alpha/{parentType}/{parent}/rules/{rule}", s.deleteNotificationRule)
+}
+
+// AddNotificationRule seeds a notification rule. The parent in the rule name must already exist.
+func (s *Server) AddNotificationRule(rule dt.NotificationRule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rules[rule.Name] = normalizeNotificationRule(rule)
+}
+
+// DeleteNotificationRule removes a notification rule, as if it was deleted outside of Terraform.
+func (s *Server) DeleteNotificationRule(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.rules, name)
+}
+
+// emptyIfNil returns an empty slice in place of a nil slice, since the API
+// always returns repeated fields as JSON arrays.
+func emptyIfNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// normalizeNotificationRule fills in the empty lists and maps the API returns
+// for fields that were omitted from the request.
+func normalizeNotificationRule(rule dt.NotificationRule) dt.NotificationRule {
+	rule.Devices = emptyIfNil(rule.Devices)
+	if rule.DeviceLabels == nil {
+		rule.DeviceLabels = map[string]string{}
+	}
+	if rule.ProjectLabels == nil {
+		rule.ProjectLabels = map[string]string{}
+	}
+	rule.EscalationLevels = emptyIfNil(rule.EscalationLevels)
+	for i := range rule.EscalationLevels {
+		rule.EscalationLevels[i].Actions = normalizeNotificationActions(rule.EscalationLevels[i].Actions)
+	}
+	rule.Actions = normalizeNotificationActions(rule.Actions) // nolint: staticcheck // Actions is deprecated, but still returned by the API.
+	return rule
+}
+
+func normalizeNotificationActions(actions []dt.NotificationAction) []dt.NotificationAction {
+	actions = emptyIfNil(actions)
+	for i := range actions {
+		if c := actions[i].SMSConfig; c != nil {
+			c.Recipients = emptyIfNil(c.Recipients)
+			c.ContactGroups = emptyIfNil(c.ContactGroups)
+		}
+		if c := actions[i].EmailConfig; c != nil {
+			c.Recipients = emptyIfNil(c.Recipients)
+			c.ContactGroups = emptyIfNil(c.ContactGroups)
+		}
+		if c := actions[i].PhoneCallConfig; c != nil {
+			c.Recipients = emptyIfNil(c.Recipients)
+			c.ContactGroups = emptyIfNil(c.ContactGroups)
+		}
+		if c := actions[i].WebhookConfig; c != nil && c.Headers == nil {
+			c.Headers = map[string]string{}
+		}
+	}
+	return actions
+}
+
+// ruleParent returns the parent resource name of the request, writing an
+// error response if the parent does not exist. The caller must hold s.mu.
+func (s *Server) ruleParent(w http.ResponseWriter, r *http.Request) (string, bool) {
+	parent := r.PathValue("parentType") + "/" + r.PathValue("parent")
+	switch r.PathValue("parentType") {
+	case "projects":
+		if _, ok := s.projects[parent]; ok {
+			return parent, true
+		}
+	case "organizations":
+		if _, ok := s.organizations[parent]; ok {
+			return parent, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "parent not found: %s", parent)
+	return "", false
+}
+
+func (s *Server) listNotificationRules(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	parent, ok := s.ruleParent(w, r)
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	rules := sortedValues(s.rules, func(rule dt.NotificationRule) bool {
+		return strings.HasPrefix(rule.Name, parent+"/rules/")
+	})
+	pageSize := s.listPageSize()
+	s.mu.Unlock()
+
+	page, nextPageToken, err := paginate(r, rules, pageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listNotificationRulesResponse{Rules: page, NextPageToken: nextPageToken})
+}
+
+func (s *Server) createNotificationRule(w http.ResponseWriter, r *http.Request) {
+	var rule dt.NotificationRule
+	if !decodeBody(w, r, &rule) {
+		return
+	}
+	if rule.Trigger.Field == "" {
+		writeError(w, http.StatusBadRequest, "trigger.field is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parent, ok := s.ruleParent(w, r)
+	if !ok {
+		return
+	}
+	rule.Name = parent + "/rules/" + newID()
+	rule = normalizeNotificationRule(rule)
+	s.rules[rule.Name] = rule
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) getNotificationRule(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("parentType") + "/" + r.PathValue("parent") + "/rules/" + r.PathValue("rule")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rule, ok := s.rules[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "notification rule not found: %s", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) updateNotificationRule(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("parentType") + "/" + r.PathValue("parent") + "/rules/" + r.PathValue("rule")
+
+	var rule dt.NotificationRule
+	if !decodeBody(w, r, &rule) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[name]; !ok {
+		writeError(w, http.StatusNotFound, "notification rule not found: %s", name)
+		return
+	}
+	rule.Name = name
+	rule = normalizeNotificationRule(rule)
+	s.rules[name] = rule
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func (s *Server) deleteNotificationRule(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("parentType") + "/" + r.PathValue("parent") + "/rules/" + r.PathValue("rule")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[name]; !ok {
+		writeError(w, http.StatusNotFound, "notification rule not found: %s", name)
+		return
+	}
+	delete(s.rules, name)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
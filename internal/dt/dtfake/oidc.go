@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// tokenLifetime is how long access tokens issued by the fake are valid.
+const tokenLifetime = time.Hour
+
+type serviceAccount struct {
+	keyID  string
+	secret string
+	email  string
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// AddServiceAccount registers a service account key that can exchange signed JWTs for access tokens.
+func (s *Server) AddServiceAccount(keyID, secret, email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.serviceAccounts[keyID] = serviceAccount{keyID: keyID, secret: secret, email: email}
+}
+
+// RevokeTokens invalidates every access token issued so far.
+func (s *Server) RevokeTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.tokens)
+}
+
+// handleToken implements the JWT bearer grant of the identity token endpoint.
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form: %s", err)
+		return
+	}
+	if grantType := r.PostForm.Get("grant_type"); grantType != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+		writeError(w, http.StatusBadRequest, "unsupported grant_type: %s", grantType)
+		return
+	}
+
+	var account serviceAccount
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(r.PostForm.Get("assertion"), claims, func(token *jwt.Token) (any, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var ok bool
+		account, ok = s.serviceAccounts[keyID]
+		if !ok {
+			return nil, fmt.Errorf("unknown key: %s", keyID)
+		}
+		return []byte(account.secret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(s.TokenEndpoint),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid assertion: %s", err)
+		return
+	}
+	if claims.Issuer != account.email {
+		writeError(w, http.StatusUnauthorized, "invalid assertion: issuer %s does not match key", claims.Issuer)
+		return
+	}
+
+	accessToken := newID() + newID()
+	s.mu.Lock()
+	s.tokens[accessToken] = time.Now().Add(tokenLifetime)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, tokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(tokenLifetime.Seconds()),
+	})
+}
+
+// authenticate rejects requests that do not carry a valid access token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		s.mu.Lock()
+		expiry, ok := s.tokens[accessToken]
+		s.mu.Unlock()
+		if !ok || time.Now().After(expiry) {
+			writeError(w, http.StatusUnauthorized, "invalid or expired access token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,208 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+type listProjectsResponse struct {
+	Projects      []dt.Project `json:"projects"`
+	NextPageToken string       `json:"nextPageToken"`
+}
+
+type createProjectRequest struct {
+	DisplayName  string      `json:"displayName"`
+	Organization string      `json:"organization"`
+	Location     dt.Location `json:"location"`
+}
+
+type batchUpdateProjectsRequest struct {
+	Projects     []string          `json:"projects"`
+	AddLabels    map[string]string `json:"addLabels"`
+	RemoveLabels []string          `json:"removeLabels"`
+}
+
+func (s *Server) registerProjectHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v2/projects", s.listProjects)
+	mux.HandleFunc("POST /v2/projects", s.createProject)
+	mux.HandleFunc("POST /v2/projects:batchUpdate", s.batchUpdateProjects)
+	mux.HandleFunc("GET /v2/projects/{project}", s.getProject)
+	mux.HandleFunc("PATCH /v2/projects/{project}", s.updateProject)
+	mux.HandleFunc("DELETE /v2/projects/{project}", s.deleteProject)
+}
+
+// AddProject seeds a project. The organization display name and the device
+// counts are filled in by the server.
+func (s *Server) AddProject(project dt.Project) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if project.Labels == nil {
+		project.Labels = map[string]string{}
+	}
+	s.projects[project.Name] = project
+}
+
+// DeleteProject removes a project, as if it was deleted outside of Terraform.
+func (s *Server) DeleteProject(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.projects, name)
+}
+
+// projectView returns the project as the API would return it. The caller must hold s.mu.
+func (s *Server) projectView(project dt.Project) dt.Project {
+	project.OrganizationDisplayName = s.organizations[project.Organization]
+	project.SensorCount = 0
+	project.CloudConnectorCount = 0
+	for _, device := range s.devices {
+		if deviceProject(device.Name) != project.Name {
+			continue
+		}
+		if device.Type == "ccon" {
+			project.CloudConnectorCount++
+		} else {
+			project.SensorCount++
+		}
+	}
+	project.Labels = maps.Clone(project.Labels)
+	return project
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	organization := r.URL.Query().Get("organization")
+
+	s.mu.Lock()
+	projects := sortedValues(s.projects, func(p dt.Project) bool {
+		return organization == "" || p.Organization == organization
+	})
+	for i := range projects {
+		projects[i] = s.projectView(projects[i])
+	}
+	pageSize := s.listPageSize()
+	s.mu.Unlock()
+
+	page, nextPageToken, err := paginate(r, projects, pageSize)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, listProjectsResponse{Projects: page, NextPageToken: nextPageToken})
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	name := "projects/" + r.PathValue("project")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project, ok := s.projects[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", name)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.projectView(project))
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	var req createProjectRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.DisplayName == "" {
+		writeError(w, http.StatusBadRequest, "displayName is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.organizations[req.Organization]; !ok {
+		writeError(w, http.StatusNotFound, "organization not found: %s", req.Organization)
+		return
+	}
+	project := dt.Project{
+		Name:         "projects/" + newID(),
+		DisplayName:  req.DisplayName,
+		Organization: req.Organization,
+		Location:     req.Location,
+		Labels:       map[string]string{},
+	}
+	if project.Location.TimeLocation == "" {
+		project.Location.TimeLocation = "UTC"
+	}
+	s.projects[project.Name] = project
+	writeJSON(w, http.StatusOK, s.projectView(project))
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	name := "projects/" + r.PathValue("project")
+
+	var req dt.EditableProject
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project, ok := s.projects[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", name)
+		return
+	}
+	if req.DisplayName != "" {
+		project.DisplayName = req.DisplayName
+	}
+	project.Location = req.Location
+	if project.Location.TimeLocation == "" {
+		project.Location.TimeLocation = "UTC"
+	}
+	s.projects[name] = project
+	writeJSON(w, http.StatusOK, s.projectView(project))
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	name := "projects/" + r.PathValue("project")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.projects[name]; !ok {
+		writeError(w, http.StatusNotFound, "project not found: %s", name)
+		return
+	}
+	for _, device := range s.devices {
+		if deviceProject(device.Name) == name {
+			writeError(w, http.StatusBadRequest, "project %s still contains devices", name)
+			return
+		}
+	}
+	delete(s.projects, name)
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) batchUpdateProjects(w http.ResponseWriter, r *http.Request) {
+	var req batchUpdateProjectsRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range req.Projects {
+		if _, ok := s.projects[name]; !ok {
+			writeError(w, http.StatusNotFound, "project not found: %s", name)
+			return
+		}
+	}
+	for _, name := range req.Projects {
+		project := s.projects[name]
+		project.Labels = maps.Clone(project.Labels)
+		for _, key := range req.RemoveLabels {
+			delete(project.Labels, key)
+		}
+		maps.Copy(project.Labels, req.AddLabels)
+		s.projects[name] = project
+	}
+	writeJSON(w, http.StatusOK, struct{}{})
+}
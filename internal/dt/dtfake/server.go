@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package dtfake implements an in-process fake of the Disruptive Technologies
+// REST API, the emulator API and the identity token endpoint.
+//
+// The fake keeps its state in memory and is meant for running the provider
+// and the dt client offline. Point the provider `url`, `emulator_url` and
+// `token_endpoint` attributes at URL, EmulatorURL and TokenEndpoint, and
+// authenticate with the KeyID, KeySecret and Email of the server.
+package dtfake
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+const (
+	// defaultPageSize is the page size used by list endpoints when the
+	// request does not specify one.
+	defaultPageSize = 100
+	// maxPageSize is the largest page size accepted by list endpoints.
+	maxPageSize = 1000
+)
+
+// Server is a stateful fake of the DT APIs backed by an httptest.Server.
+type Server struct {
+	// URL is the base URL of the fake REST API.
+	URL string
+	// EmulatorURL is the base URL of the fake emulator API.
+	EmulatorURL string
+	// TokenEndpoint is the URL of the fake OIDC token endpoint.
+	TokenEndpoint string
+
+	// KeyID, KeySecret and Email are the credentials of the service account
+	// that is registered when the server is created.
+	KeyID     string
+	KeySecret string
+	Email     string
+
+	server *httptest.Server
+
+	mu              sync.Mutex
+	serviceAccounts map[string]serviceAccount
+	tokens          map[string]time.Time
+	pageSize        int
+	organizations   map[string]string
+	projects        map[string]dt.Project
+	devices         map[string]dt.Device
+	claimable       map[string]claimableDevice
+	kits            map[string][]string
+	events          map[string][]dt.Event
+	dataConnectors  map[string]dt.DataConnector
+	rules           map[string]dt.NotificationRule
+	contacts        map[string]dt.Contact
+	contactGroups   map[string]dt.ContactGroup
+	members         map[string]dt.Membership
+	memberIDs       map[string]string
+}
+
+// NewServer starts a new fake server with a single registered service account.
+// The caller must call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		KeyID:           newID(),
+		KeySecret:       newID() + newID(),
+		serviceAccounts: make(map[string]serviceAccount),
+		tokens:          make(map[string]time.Time),
+		organizations:   make(map[string]string),
+		projects:        make(map[string]dt.Project),
+		devices:         make(map[string]dt.Device),
+		claimable:       make(map[string]claimableDevice),
+		kits:            make(map[string][]string),
+		events:          make(map[string][]dt.Event),
+		dataConnectors:  make(map[string]dt.DataConnector),
+		rules:           make(map[string]dt.NotificationRule),
+		contacts:        make(map[string]dt.Contact),
+		contactGroups:   make(map[string]dt.ContactGroup),
+		members:         make(map[string]dt.Membership),
+		memberIDs:       make(map[string]string),
+	}
+	s.Email = s.KeyID + "@fake.serviceaccount.d21s.com"
+	s.AddServiceAccount(s.KeyID, s.KeySecret, s.Email)
+
+	mux := http.NewServeMux()
+	s.registerProjectHandlers(mux)
+	s.registerDeviceHandlers(mux)
+	s.registerClaimHandlers(mux)
+	s.registerEventHandlers(mux)
+	s.registerDataConnectorHandlers(mux)
+	s.registerNotificationRuleHandlers(mux)
+	s.registerContactHandlers(mux)
+	s.registerMemberHandlers(mux)
+
+	emulatorMux := http.NewServeMux()
+	s.registerEmulatorHandlers(emulatorMux)
+
+	root := http.NewServeMux()
+	root.HandleFunc("POST /oauth2/token", s.handleToken)
+	root.Handle("/emulator/", http.StripPrefix("/emulator", s.authenticate(emulatorMux)))
+	root.Handle("/", s.authenticate(mux))
+
+	s.server = httptest.NewServer(root)
+	s.URL = s.server.URL
+	s.EmulatorURL = s.server.URL + "/emulator"
+	s.TokenEndpoint = s.server.URL + "/oauth2/token"
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests have completed.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetPageSize caps the page size of the list endpoints, regardless of the
+// page size requested by the client. Use it to exercise pagination with few
+// resources.
+func (s *Server) SetPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = pageSize
+}
+
+// AddOrganization registers an organization that projects and contact groups can be created in.
+func (s *Server) AddOrganization(name, displayName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.organizations[name] = displayName
+}
+
+// errorResponse is the error envelope returned by the DT API.
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  int    `json:"code"`
+	Help  string `json:"help"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorResponse{
+		Error: fmt.Sprintf(format, args...),
+		Code:  status,
+		Help:  fmt.Sprintf("https://developer.disruptive-technologies.com/docs/error-codes#%d", status),
+	})
+}
+
+// decodeBody decodes the JSON request body into v, writing a 400 response on failure.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: %s", err)
+		return false
+	}
+	return true
+}
+
+// paginate returns the page of items selected by the pageSize and pageToken
+// query parameters of the request, and the token for the next page. Page
+// sizes are capped at limit.
+func paginate[T any](r *http.Request, items []T, limit int) ([]T, string, error) {
+	pageSize := min(defaultPageSize, limit)
+	if value := r.URL.Query().Get("pageSize"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return nil, "", fmt.Errorf("invalid pageSize: %s", value)
+		}
+		if size > 0 {
+			pageSize = min(size, limit)
+		}
+	}
+
+	offset := 0
+	if token := r.URL.Query().Get("pageToken"); token != "" {
+		o, err := strconv.Atoi(token)
+		if err != nil || o < 0 || o > len(items) {
+			return nil, "", fmt.Errorf("invalid pageToken: %s", token)
+		}
+		offset = o
+	}
+
+	end := min(offset+pageSize, len(items))
+	nextPageToken := ""
+	if end < len(items) {
+		nextPageToken = strconv.Itoa(end)
+	}
+	return items[offset:end], nextPageToken, nil
+}
+
+// listPageSize returns the largest page size of the list endpoints. The caller must hold s.mu.
+func (s *Server) listPageSize() int {
+	if s.pageSize > 0 {
+		return s.pageSize
+	}
+	return maxPageSize
+}
+
+// sortedValues returns the values of m ordered by key, so that list
+// endpoints return a stable order across pages.
+func sortedValues[T any](m map[string]T, keep func(T) bool) []T {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	values := make([]T, 0, len(keys))
+	for _, key := range keys {
+		if keep == nil || keep(m[key]) {
+			values = append(values, m[key])
+		}
+	}
+	return values
+}
+
+// idAlphabet is the alphabet used by xid, which DT uses for resource IDs.
+const idAlphabet = "0123456789abcdefghijklmnopqrstuv"
+
+// newID returns a random 20 character identifier in the same format as DT resource IDs.
+func newID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	for i := range b {
+		b[i] = idAlphabet[int(b[i])%len(idAlphabet)]
+	}
+	return string(b)
+}
+
+// lastSegment returns the part of a resource name after the last slash.
+func lastSegment(name string) string {
+	return name[strings.LastIndex(name, "/")+1:]
+}
@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dtfake_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/dtfake"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+)
+
+const testOrganization = "organizations/cvinmt9aq9sc738g6eog"
+
+func newTestClient(t *testing.T) (*dtfake.Server, *dt.Client) {
+	t.Helper()
+	server := dtfake.NewServer()
+	t.Cleanup(server.Close)
+	server.AddOrganization(testOrganization, "Test Org")
+
+	client := dt.NewClient(dt.Config{
+		URL:         server.URL,
+		EmulatorURL: server.EmulatorURL,
+		Version:     "test",
+		Oidc: oidc.Config{
+			TokenEndpoint: server.TokenEndpoint,
+			ClientID:      server.KeyID,
+			ClientSecret:  server.KeySecret,
+			Email:         server.Email,
+		},
+	})
+	return server, client
+}
+
+func TestProjectLifecycle(t *testing.T) {
+	t.Parallel()
+	_, client := newTestClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateProject(ctx, dt.Project{
+		DisplayName:  "fake project",
+		Organization: testOrganization,
+		Labels:       map[string]string{"foo": "bar"},
+	})
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if created.Labels["foo"] != "bar" {
+		t.Errorf("created project labels = %v, want foo=bar", created.Labels)
+	}
+
+	project, err := client.GetProject(ctx, created.Name, testOrganization)
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project.OrganizationDisplayName != "Test Org" {
+		t.Errorf("OrganizationDisplayName = %q, want %q", project.OrganizationDisplayName, "Test Org")
+	}
+	if project.Location.TimeLocation != "UTC" {
+		t.Errorf("TimeLocation = %q, want UTC", project.Location.TimeLocation)
+	}
+
+	if err := client.DeleteProject(ctx, created.Name); err != nil {
+		t.Fatalf("DeleteProject() error = %v", err)
+	}
+	if err := client.DeleteProject(ctx, created.Name); !errors.Is(err, dt.ErrNotFound) {
+		t.Errorf("second DeleteProject() error = %v, want %v", err, dt.ErrNotFound)
+	}
+}
+
+func TestEmulatorIsVisibleAsDevice(t *testing.T) {
+	t.Parallel()
+	server, client := newTestClient(t)
+	ctx := context.Background()
+	server.AddProject(dt.Project{Name: "projects/cvinutal2ugc73b866v0", Organization: testOrganization})
+
+	emulator, err := client.CreateEmulator(ctx, "cvinutal2ugc73b866v0", dt.Emulator{
+		Type:   "temperature",
+		Labels: map[string]string{"name": "sensor"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEmulator() error = %v", err)
+	}
+
+	device, err := client.GetDevice(ctx, emulator.Name)
+	if err != nil {
+		t.Fatalf("GetDevice() error = %v", err)
+	}
+	if device.Type != "temperature" || device.Labels["name"] != "sensor" {
+		t.Errorf("GetDevice() = %+v, want temperature device named sensor", device)
+	}
+
+	project, err := client.GetProject(ctx, "projects/cvinutal2ugc73b866v0", testOrganization)
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project.SensorCount != 1 {
+		t.Errorf("SensorCount = %d, want 1", project.SensorCount)
+	}
+}
+
+func TestNotificationRuleLifecycle(t *testing.T) {
+	t.Parallel()
+	server, client := newTestClient(t)
+	ctx := context.Background()
+	server.AddProject(dt.Project{Name: "projects/d0919uq3tjjs739bf18g", Organization: testOrganization})
+
+	created, err := client.CreateNotificationRule(ctx, "projects/d0919uq3tjjs739bf18g", dt.NotificationRule{
+		DisplayName: "rule",
+		Trigger:     dt.Trigger{Field: "temperature"},
+	})
+	if err != nil {
+		t.Fatalf("CreateNotificationRule() error = %v", err)
+	}
+
+	rule, err := client.GetNotificationRule(ctx, created.Name)
+	if err != nil {
+		t.Fatalf("GetNotificationRule() error = %v", err)
+	}
+	if rule.DisplayName != "rule" {
+		t.Errorf("DisplayName = %q, want %q", rule.DisplayName, "rule")
+	}
+
+	if err := client.DeleteNotificationRule(ctx, created.Name); err != nil {
+		t.Fatalf("DeleteNotificationRule() error = %v", err)
+	}
+}
+
+func TestMembershipsArePaged(t *testing.T) {
+	t.Parallel()
+	server, client := newTestClient(t)
+	ctx := context.Background()
+	server.SetPageSize(2)
+
+	email := "d0hjenj24tsg00b24tb0@cvinmt9aq9sc738g6ep0.serviceaccount.d21s.com"
+	var req dt.BatchCreateProjectsMembersRequest
+	for _, id := range []string{"d0hj3ndaoups738bc8og", "d0hj3qdaoups738bc8pg", "d0hj3s5aoups738bc8qg"} {
+		server.AddProject(dt.Project{Name: "projects/" + id, Organization: testOrganization})
+		req.Members = append(req.Members, dt.Members{
+			Project: "projects/" + id,
+			Email:   email,
+			Roles:   []string{"roles/project.user"},
+		})
+	}
+	if _, err := client.BatchCreateMemberships(ctx, req); err != nil {
+		t.Fatalf("BatchCreateMemberships() error = %v", err)
+	}
+
+	members, err := client.ListProjectMemberships(ctx, testOrganization, "roles/project.user", "d0hjenj24tsg00b24tb0")
+	if err != nil {
+		t.Fatalf("ListProjectMemberships() error = %v", err)
+	}
+	if len(members) != 3 {
+		t.Errorf("ListProjectMemberships() returned %d members, want 3", len(members))
+	}
+}
+
+func TestRejectsInvalidCredentials(t *testing.T) {
+	t.Parallel()
+	server := dtfake.NewServer()
+	t.Cleanup(server.Close)
+
+	client := dt.NewClient(dt.Config{
+		URL: server.URL,
+		Oidc: oidc.Config{
+			TokenEndpoint: server.TokenEndpoint,
+			ClientID:      server.KeyID,
+			ClientSecret:  "wrong secret",
+			Email:         server.Email,
+		},
+	})
+	_, err := client.GetDevice(context.Background(), "projects/p/devices/d")
+	if !errors.Is(err, dt.ErrUnauthenticated) {
+		t.Fatalf("GetDevice() with invalid credentials error = %v, want %v", err, dt.ErrUnauthenticated)
+	}
+}
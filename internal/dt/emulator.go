@@ -6,7 +6,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 )
 
 type Emulator struct {
@@ -16,12 +19,12 @@ type Emulator struct {
 }
 
 func (c *Client) GetEmulator(ctx context.Context, name string) (Emulator, error) {
-	projectID, deviceID, err := ParseResourceName(name)
+	deviceName, err := names.ParseDeviceName(name)
 	if err != nil {
 		return Emulator{}, err
 	}
 
-	url := c.EmulatorURL + "/v2/projects/" + projectID + "/devices/" + deviceID
+	url := c.EmulatorURL + "/v2/" + deviceName.String()
 	responseBody, err := c.DoRequest(ctx, "GET", url, nil, nil)
 	if err != nil {
 		return Emulator{}, err
@@ -50,30 +53,34 @@ func (c *Client) CreateEmulator(ctx context.Context, projectID string, emulatorT
 	if err := json.Unmarshal(responseBody, &createdEmulator); err != nil {
 		return Emulator{}, err
 	}
+	// The device counts of the cached project are out of date.
+	c.projectCache.delete(names.ProjectName{Project: projectID}.String())
 	return createdEmulator, nil
 }
 
 func (c *Client) DeleteEmulator(ctx context.Context, name string) error {
-	projectID, deviceID, err := ParseResourceName(name)
+	deviceName, err := names.ParseDeviceName(name)
 	if err != nil {
 		return err
 	}
 
-	url := c.EmulatorURL + "/v2/projects/" + projectID + "/devices/" + deviceID
+	url := c.EmulatorURL + "/v2/" + deviceName.String()
 	_, err = c.DoRequest(ctx, "DELETE", url, nil, nil)
 	if err != nil {
 		return err
 	}
+	// The device counts of the cached project are out of date.
+	c.projectCache.delete(deviceName.ProjectName().String())
 	return nil
 }
 
 func (c *Client) UpdateEmulator(ctx context.Context, emulator Emulator) (Emulator, error) {
-	projectID, deviceID, err := ParseResourceName(emulator.Name)
+	deviceName, err := names.ParseDeviceName(emulator.Name)
 	if err != nil {
 		return Emulator{}, err
 	}
 
-	url := c.EmulatorURL + "/v2/projects/" + projectID + "/devices/" + deviceID
+	url := c.EmulatorURL + "/v2/" + deviceName.String()
 
 	body, err := json.Marshal(emulator)
 	if err != nil {
@@ -92,20 +99,37 @@ func (c *Client) UpdateEmulator(ctx context.Context, emulator Emulator) (Emulato
 	return updatedEmulator, nil
 }
 
-func (e *Emulator) ProjectID() string {
-	projectID, _, _ := parseEmulatorResourceName(e.Name)
-	return projectID
+// PublishEmulatorEvent publishes an event from an emulated device, as if it
+// was sent by a physical device of the same type. The event is the block of
+// the reported state for its event type, such as Temperature or
+// ConnectionStatus, and exactly one block must be set. The emulator sets the
+// update time of the event if it is empty.
+func (c *Client) PublishEmulatorEvent(ctx context.Context, name string, event Reported) error {
+	deviceName, err := names.ParseDeviceName(name)
+	if err != nil {
+		return fmt.Errorf("dt: failed to parse resource name: %w", err)
+	}
+	if eventTypes := event.EventTypes(); len(eventTypes) != 1 {
+		return fmt.Errorf("dt: an emulator event must have exactly one event type, got %d: %s", len(eventTypes), strings.Join(eventTypes, ", "))
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("dt: failed to marshal emulator event: %w", err)
+	}
+
+	url := c.EmulatorURL + "/v2/" + deviceName.String() + ":publish"
+	if _, err := c.DoRequest(ctx, http.MethodPost, url, body, nil); err != nil {
+		return fmt.Errorf("dt: failed to publish emulator event: %w", err)
+	}
+	return nil
 }
 
-func (e *Emulator) DeviceID() string {
-	_, deviceID, _ := parseEmulatorResourceName(e.Name)
-	return deviceID
+func (e *Emulator) ProjectID() string {
+	deviceName, _ := names.ParseDeviceName(e.Name)
+	return deviceName.Project
 }
 
-func parseEmulatorResourceName(name string) (string, string, error) {
-	parts := strings.Split(name, "/")
-	if len(parts) != 4 {
-		return "", "", fmt.Errorf("invalid resource name: %s", name)
-	}
-	return parts[1], parts[3], nil
+func (e *Emulator) DeviceID() string {
+	deviceName, _ := names.ParseDeviceName(e.Name)
+	return deviceName.Device
 }
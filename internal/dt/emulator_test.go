@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func TestPublishEmulatorEvent(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	ctx := context.Background()
+
+	emulator, err := client.CreateEmulator(ctx, "p1", dt.Emulator{Type: "temperature"})
+	if err != nil {
+		t.Fatalf("CreateEmulator() error = %v", err)
+	}
+	for _, event := range []dt.Reported{
+		{Temperature: &dt.Temperature{Value: 21.5}},
+		{NetworkStatus: &dt.NetworkStatus{SignalStrength: 80, RSSI: -60, UpdateTime: "2025-03-12T09:00:00Z"}},
+	} {
+		if err := client.PublishEmulatorEvent(ctx, emulator.Name, event); err != nil {
+			t.Fatalf("PublishEmulatorEvent(%v) error = %v", event.EventTypes(), err)
+		}
+	}
+
+	device, err := client.GetDevice(ctx, emulator.Name)
+	if err != nil {
+		t.Fatalf("GetDevice() error = %v", err)
+	}
+	if got := device.Reported.EventTypes(); len(got) != 2 || device.Reported.Temperature.Value != 21.5 || device.Reported.Temperature.UpdateTime == "" {
+		t.Errorf("Reported = %+v, want the published temperature with an update time, and network status", device.Reported)
+	}
+
+	var eventTypes []string
+	for event, err := range client.ListEvents(ctx, emulator.Name, dt.ListEventsFilter{}) {
+		if err != nil {
+			t.Fatalf("ListEvents() error = %v", err)
+		}
+		eventTypes = append(eventTypes, event.EventType)
+		if event.EventType == "temperature" {
+			var data dt.Reported
+			if err := json.Unmarshal(event.Data, &data); err != nil || data.Temperature == nil || data.Temperature.Value != 21.5 {
+				t.Errorf("temperature event data = %s, want the published value", event.Data)
+			}
+		}
+	}
+	if len(eventTypes) != 2 {
+		t.Errorf("event types = %v, want the published events", eventTypes)
+	}
+}
+
+func TestPublishEmulatorEventRequiresOneEventType(t *testing.T) {
+	t.Parallel()
+	_, client := newFakeTestClient(t, 0)
+	for _, event := range []dt.Reported{
+		{},
+		{Touch: &dt.Touch{}, Motion: &dt.Motion{State: "MOTION_DETECTED"}},
+	} {
+		if err := client.PublishEmulatorEvent(context.Background(), "projects/p1/devices/emu1", event); err == nil {
+			t.Errorf("PublishEmulatorEvent(%v) succeeded, want an error", event.EventTypes())
+		}
+	}
+}
+
+func TestReportedEventTypes(t *testing.T) {
+	t.Parallel()
+	reported := dt.Reported{
+		Touch:            &dt.Touch{},
+		Contact:          &dt.ContactState{State: "OPEN"},
+		ConnectionStatus: &dt.ConnectionStatus{Connection: "ETHERNET"},
+	}
+	got := reported.EventTypes()
+	if want := []string{"connectionStatus", "contact", "touch"}; !slices.Equal(got, want) {
+		t.Errorf("EventTypes() = %v, want %v", got, want)
+	}
+
+	var none *dt.Reported
+	if got := none.EventTypes(); len(got) != 0 {
+		t.Errorf("EventTypes() of no reported state = %v, want none", got)
+	}
+}
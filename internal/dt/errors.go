@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors returned by the client, comparable with errors.Is. Errors returned
+// by the API are HTTPErrors that match the sentinel for their status code.
+var (
+	// ErrInvalidArgument is returned when the API rejects the request with 400 Bad Request.
+	ErrInvalidArgument = errors.New("invalid argument")
+	// ErrUnauthenticated is returned when the API rejects the access token with 401 Unauthorized.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrPermissionDenied is returned when the service account lacks the role
+	// required for the request, with 403 Forbidden.
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrNotFound is returned when the API responds with 404 Not Found, or
+	// when a resource is missing from a list response, such as when GetProject
+	// or GetNotificationRule can not find the resource after populating their
+	// caches.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists is returned when the API responds with 409 Conflict.
+	ErrAlreadyExists = errors.New("already exists")
+)
+
+// HTTPError is returned when the API responds with a status code other than 200 OK.
+type HTTPError struct {
+	StatusCode int
+	// Body is the raw response body.
+	Body string
+
+	// Message, Code, Help and Details are decoded from the error envelope of
+	// the response body, and are empty if the body is not an error envelope.
+	Message string
+	Code    int
+	Help    string
+	Details []json.RawMessage
+}
+
+// errorEnvelope is the body of error responses from the DT API.
+type errorEnvelope struct {
+	Error   string            `json:"error"`
+	Message string            `json:"message"`
+	Code    int               `json:"code"`
+	Help    string            `json:"help"`
+	Details []json.RawMessage `json:"details"`
+}
+
+// newHTTPError returns an HTTPError for the response, decoding the error
+// envelope of the body if there is one.
+func newHTTPError(statusCode int, body []byte) *HTTPError {
+	httpErr := &HTTPError{
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		httpErr.Message = envelope.Message
+		if httpErr.Message == "" {
+			httpErr.Message = envelope.Error
+		}
+		httpErr.Code = envelope.Code
+		httpErr.Help = envelope.Help
+		httpErr.Details = envelope.Details
+	}
+	return httpErr
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("HTTP error: %d: %s", e.StatusCode, e.Body)
+	}
+	// The code is usually the status code again, and only shown when it adds to it.
+	if e.Code == 0 || e.Code == e.StatusCode {
+		return fmt.Sprintf("HTTP error: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+	}
+	return fmt.Sprintf("HTTP error: %d %s: %s (code %d)", e.StatusCode, http.StatusText(e.StatusCode), e.Message, e.Code)
+}
+
+// Is reports whether the error matches one of the sentinel errors, based on the status code.
+func (e *HTTPError) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return target == ErrInvalidArgument
+	case http.StatusUnauthorized:
+		return target == ErrUnauthenticated
+	case http.StatusForbidden:
+		return target == ErrPermissionDenied
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrAlreadyExists
+	}
+	return false
+}
+
+// IsNotFound reports whether err indicates that the requested resource does
+// not exist, either because the API responded with 404 Not Found or because
+// the resource was missing from a list response.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
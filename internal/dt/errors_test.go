@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func TestHTTPErrorIs(t *testing.T) {
+	t.Parallel()
+	sentinels := []error{dt.ErrInvalidArgument, dt.ErrUnauthenticated, dt.ErrPermissionDenied, dt.ErrNotFound, dt.ErrAlreadyExists}
+
+	tests := []struct {
+		statusCode int
+		want       error
+	}{
+		{http.StatusBadRequest, dt.ErrInvalidArgument},
+		{http.StatusUnauthorized, dt.ErrUnauthenticated},
+		{http.StatusForbidden, dt.ErrPermissionDenied},
+		{http.StatusNotFound, dt.ErrNotFound},
+		{http.StatusConflict, dt.ErrAlreadyExists},
+		{http.StatusInternalServerError, nil},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.statusCode), func(t *testing.T) {
+			t.Parallel()
+			// Respond twice with the status code, since requests that fail
+			// with 401 Unauthorized are sent again with a new token.
+			client, _ := newRetryTestClient(t, dt.RetryPolicy{MaxAttempts: 1}, tt.statusCode, tt.statusCode)
+
+			_, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/v2/projects", nil, nil)
+			// Wrap the error like the client methods do.
+			err = fmt.Errorf("dt: failed to get project: %w", err)
+			for _, sentinel := range sentinels {
+				if got, want := errors.Is(err, sentinel), sentinel == tt.want; got != want {
+					t.Errorf("errors.Is(%v, %v) = %t, want %t", err, sentinel, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPErrorDecodesEnvelope(t *testing.T) {
+	t.Parallel()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"error":"missing permission projects.update","code":403,"help":"https://example.com/errors#403","details":[{"permission":"projects.update"}]}`))
+	}))
+	t.Cleanup(api.Close)
+	client, _ := newRetryTestClient(t, dt.RetryPolicy{})
+
+	_, err := client.DoRequest(context.Background(), http.MethodGet, api.URL, nil, nil)
+	var httpErr *dt.HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("DoRequest() error = %v, want *dt.HTTPError", err)
+	}
+	if httpErr.Message != "missing permission projects.update" {
+		t.Errorf("Message = %q, want %q", httpErr.Message, "missing permission projects.update")
+	}
+	if httpErr.Code != 403 {
+		t.Errorf("Code = %d, want 403", httpErr.Code)
+	}
+	if httpErr.Help != "https://example.com/errors#403" {
+		t.Errorf("Help = %q, want %q", httpErr.Help, "https://example.com/errors#403")
+	}
+	if len(httpErr.Details) != 1 || string(httpErr.Details[0]) != `{"permission":"projects.update"}` {
+		t.Errorf("Details = %s, want one permission detail", httpErr.Details)
+	}
+	if want := "HTTP error: 403 Forbidden: missing permission projects.update"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	// The code is only shown when it is not the status code.
+	withCode := &dt.HTTPError{StatusCode: http.StatusBadRequest, Message: "invalid filter", Code: 3}
+	if want := "HTTP error: 400 Bad Request: invalid filter (code 3)"; withCode.Error() != want {
+		t.Errorf("Error() = %q, want %q", withCode.Error(), want)
+	}
+}
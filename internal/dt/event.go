@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+)
+
+// Event is an event published by a device, such as a temperature reading or
+// a touch. Data has a single field named after the event type, with the same
+// format as the field of the reported state, such as
+// {"temperature": {"value": 21.5, "updateTime": "..."}}.
+type Event struct {
+	EventID    string          `json:"eventId"`
+	TargetName string          `json:"targetName"`
+	EventType  string          `json:"eventType"`
+	Data       json.RawMessage `json:"data"`
+	Timestamp  string          `json:"timestamp"`
+}
+
+// ListEventsFilter selects the events to list. Empty fields do not filter.
+type ListEventsFilter struct {
+	// EventTypes are the types of events to list, such as temperature or
+	// networkStatus.
+	EventTypes []string
+	// StartTime and EndTime are the time window of the events.
+	StartTime time.Time
+	EndTime   time.Time
+	// PageSize is the number of events requested per page, such as the
+	// number of events the caller is going to read. It defaults to the page
+	// size of the client, and is capped at MaxPageSize.
+	PageSize int
+}
+
+// query returns the query parameters of the filter.
+func (f ListEventsFilter) query() url.Values {
+	query := url.Values{}
+	for _, eventType := range f.EventTypes {
+		query.Add("eventTypes", eventType)
+	}
+	if !f.StartTime.IsZero() {
+		query.Set("startTime", f.StartTime.UTC().Format(time.RFC3339Nano))
+	}
+	if !f.EndTime.IsZero() {
+		query.Set("endTime", f.EndTime.UTC().Format(time.RFC3339Nano))
+	}
+	return query
+}
+
+type ListEventsResponse struct {
+	Events        []Event `json:"events"`
+	NextPageToken string  `json:"nextPageToken"`
+}
+
+// ListEvents returns an iterator over the event history of a device, newest
+// first. Stop the iteration to avoid requesting more pages than needed.
+func (c *Client) ListEvents(ctx context.Context, device string, filter ListEventsFilter) iter.Seq2[Event, error] {
+	deviceName, err := names.ParseDeviceName(device)
+	if err != nil {
+		return func(yield func(Event, error) bool) {
+			yield(Event{}, fmt.Errorf("dt: failed to parse resource name: %w", err))
+		}
+	}
+
+	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}/devices/{device_id}/events
+	url := fmt.Sprintf("%s/v2/%s/events", strings.TrimSuffix(c.URL, "/"), deviceName)
+	if query := filter.query(); len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+	var params map[string]string
+	if filter.PageSize > 0 {
+		params = map[string]string{"pageSize": strconv.Itoa(min(filter.PageSize, MaxPageSize))}
+	}
+	return paginate(ctx, c, url, params, func(body []byte) ([]Event, string, error) {
+		var events ListEventsResponse
+		if err := json.Unmarshal(body, &events); err != nil {
+			return nil, "", fmt.Errorf("dt: failed to unmarshal events: %w", err)
+		}
+		return events.Events, events.NextPageToken, nil
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func listEventIDs(t *testing.T, client *dt.Client, filter dt.ListEventsFilter) []string {
+	t.Helper()
+	var got []string
+	for event, err := range client.ListEvents(context.Background(), "projects/p1/devices/d1", filter) {
+		if err != nil {
+			t.Fatalf("ListEvents(%+v) error = %v", filter, err)
+		}
+		got = append(got, event.EventID)
+	}
+	return got
+}
+
+func TestListEvents(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 2)
+	addTestDevices(server)
+	for _, event := range []dt.Event{
+		{EventID: "e1", EventType: "temperature", Timestamp: "2025-03-12T09:00:00Z", Data: json.RawMessage(`{"temperature": {"value": 20.5}}`)},
+		{EventID: "e2", EventType: "networkStatus", Timestamp: "2025-03-12T09:15:00Z", Data: json.RawMessage(`{"networkStatus": {"signalStrength": 80}}`)},
+		{EventID: "e3", EventType: "temperature", Timestamp: "2025-03-12T09:30:00Z", Data: json.RawMessage(`{"temperature": {"value": 21.5}}`)},
+		{EventID: "e4", EventType: "temperature", Timestamp: "2025-03-12T09:45:00.5Z", Data: json.RawMessage(`{"temperature": {"value": 22.5}}`)},
+		{EventID: "e5", EventType: "batteryStatus", Timestamp: "2025-03-12T10:00:00Z", Data: json.RawMessage(`{"batteryStatus": {"percentage": 100}}`)},
+	} {
+		event.TargetName = "projects/p1/devices/d1"
+		server.AddEvent(event)
+	}
+
+	tests := []struct {
+		name   string
+		filter dt.ListEventsFilter
+		want   []string
+	}{
+		{
+			name: "all events, over several pages",
+			want: []string{"e5", "e4", "e3", "e2", "e1"},
+		},
+		{
+			name:   "event types",
+			filter: dt.ListEventsFilter{EventTypes: []string{"temperature", "batteryStatus"}},
+			want:   []string{"e5", "e4", "e3", "e1"},
+		},
+		{
+			name: "time window",
+			filter: dt.ListEventsFilter{
+				StartTime: time.Date(2025, 3, 12, 9, 15, 0, 0, time.UTC),
+				EndTime:   time.Date(2025, 3, 12, 9, 45, 0, 0, time.UTC),
+			},
+			want: []string{"e3", "e2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := listEventIDs(t, client, tt.filter); !slices.Equal(got, tt.want) {
+				t.Errorf("ListEvents(%+v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListEventsSendsFilters(t *testing.T) {
+	t.Parallel()
+	var query url.Values
+	_, client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{"events": []}`))
+	}), nil)
+
+	filter := dt.ListEventsFilter{
+		EventTypes: []string{"touch", "temperature"},
+		StartTime:  time.Date(2025, 3, 12, 10, 0, 0, 0, time.FixedZone("CET", 3600)),
+	}
+	for _, err := range client.ListEvents(context.Background(), "projects/p1/devices/d1", filter) {
+		if err != nil {
+			t.Fatalf("ListEvents() error = %v", err)
+		}
+	}
+	if got := query["eventTypes"]; !slices.Equal(got, []string{"touch", "temperature"}) {
+		t.Errorf("eventTypes = %v, want touch and temperature", got)
+	}
+	if got := query.Get("startTime"); got != "2025-03-12T09:00:00Z" {
+		t.Errorf("startTime = %q, want the time in UTC", got)
+	}
+	if query.Has("endTime") {
+		t.Errorf("endTime = %q, want it to be left out", query.Get("endTime"))
+	}
+}
+
+func TestListEventsPageSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		pageSize int
+		want     string
+	}{
+		{name: "default", want: strconv.Itoa(dt.DefaultPageSize)},
+		{name: "limit", pageSize: 5, want: "5"},
+		{name: "capped", pageSize: 5000, want: strconv.Itoa(dt.MaxPageSize)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var pageSize string
+			_, client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				pageSize = r.URL.Query().Get("pageSize")
+				_, _ = w.Write([]byte(`{"events": []}`))
+			}), nil)
+			for _, err := range client.ListEvents(context.Background(), "projects/p1/devices/d1", dt.ListEventsFilter{PageSize: tt.pageSize}) {
+				if err != nil {
+					t.Fatalf("ListEvents() error = %v", err)
+				}
+			}
+			if pageSize != tt.want {
+				t.Errorf("pageSize = %q, want %q", pageSize, tt.want)
+			}
+		})
+	}
+}
+
+func TestListEventsNotFound(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	addTestDevices(server)
+	for _, err := range client.ListEvents(context.Background(), "projects/p1/devices/missing", dt.ListEventsFilter{}) {
+		if !dt.IsNotFound(err) {
+			t.Errorf("ListEvents() error = %v, want not found", err)
+		}
+	}
+	for _, err := range client.ListEvents(context.Background(), "d1", dt.ListEventsFilter{}) {
+		if err == nil {
+			t.Error("ListEvents() with a device ID succeeded, want an error")
+		}
+	}
+}
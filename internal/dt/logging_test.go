@@ -0,0 +1,44 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestDebugLogsAreRedacted(t *testing.T) {
+	t.Parallel()
+	const signatureSecret = "webhook-signature-secret"
+
+	for _, unsafe := range []bool{false, true} {
+		_, client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"name":"projects/p/dataconnectors/d","httpConfig":{"signatureSecret":"` + signatureSecret + `"}}`))
+		}), func(cfg *dt.Config) {
+			cfg.UnsafeLogging = unsafe
+			cfg.Oidc.UnsafeLogging = unsafe
+		})
+
+		var logs bytes.Buffer
+		ctx := tflogtest.RootLogger(context.Background(), &logs)
+		if _, err := client.GetDataConnector(ctx, "projects/p/dataconnectors/d"); err != nil {
+			t.Fatalf("GetDataConnector() error = %v", err)
+		}
+
+		for name, secret := range map[string]string{
+			"signature secret": signatureSecret,
+			// The signed JWT assertion sent to the token endpoint.
+			"JWT assertion": "eyJ",
+		} {
+			if got := strings.Contains(logs.String(), secret); got != unsafe {
+				t.Errorf("with unsafe logging %t, logs contain the %s = %t, want %t", unsafe, name, got, unsafe)
+			}
+		}
+	}
+}
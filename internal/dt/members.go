@@ -6,8 +6,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -48,53 +48,40 @@ type Membership struct {
 }
 
 func (m Membership) ProjectID() (string, error) {
-	projectID, _, err := ParseResourceName(m.Name)
+	memberName, err := names.ParseMemberName(m.Name)
 	if err != nil {
 		return "", fmt.Errorf("dt: failed to parse resource name: %w", err)
 	}
-	return projectID, nil
+	return memberName.Project, nil
 }
 
 func (m Membership) ID() (string, error) {
-	_, memberID, err := ParseResourceName(m.Name)
+	memberName, err := names.ParseMemberName(m.Name)
 	if err != nil {
 		return "", fmt.Errorf("dt: failed to parse resource name: %w", err)
 	}
-	return memberID, nil
+	return memberName.Member, nil
 }
 
 // ListProjectMemberships lists all memberships for a given organization and member.
 func (c *Client) ListProjectMemberships(ctx context.Context, organization, role, memberID string) ([]Membership, error) {
-	var members []Membership
-
 	params := map[string]string{
 		"memberId":     memberID,
 		"organization": organization,
-		"pageSize":     "100", // Default page size
-		"pageToken":    "",
 	}
 
 	// use the project wildcard to list all memberships across all projects in the organization:
 	url := c.URL + "/v2/projects/-/members"
 
-	for {
-		responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, params)
-		if err != nil {
-			return nil, err
+	members, err := collect(paginate(ctx, c, url, params, func(body []byte) ([]Membership, string, error) {
+		var response ListProjectMembersResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, "", err
 		}
-
-		var member ListProjectMembersResponse
-		if err := json.Unmarshal(responseBody, &member); err != nil {
-			return nil, err
-		}
-		members = append(members, member.Members...)
-
-		if member.NextPageToken == "" {
-			break
-		}
-
-		// If there is a next page token, set it for the next request:
-		params["pageToken"] = member.NextPageToken
+		return response.Members, response.NextPageToken, nil
+	}))
+	if err != nil {
+		return nil, err
 	}
 	tflog.Debug(ctx, fmt.Sprintf("dt: found %d memberships for member %s in organization %s", len(members), memberID, organization))
 
@@ -145,7 +132,7 @@ type UpdateProjectMemberRequest struct {
 func (c *Client) UpdateMemberships(ctx context.Context, memberships []Membership, role string) ([]Membership, error) {
 	updatedMembers := make([]Membership, 0, len(memberships))
 	for _, member := range memberships {
-		projectID, memberID, err := ParseResourceName(member.Name)
+		memberName, err := names.ParseMemberName(member.Name)
 		if err != nil {
 			return nil, fmt.Errorf("dt: failed to parse resource name: %w", err)
 		}
@@ -153,7 +140,7 @@ func (c *Client) UpdateMemberships(ctx context.Context, memberships []Membership
 		// This api only allows a single role to be set for a member:
 		member.Roles = []string{role}
 
-		url := c.URL + "/v2/projects/" + projectID + "/members/" + memberID
+		url := c.URL + "/v2/" + memberName.String()
 		requestBody, err := json.Marshal(member)
 		if err != nil {
 			return nil, fmt.Errorf("dt: failed to marshal memberships: %w", err)
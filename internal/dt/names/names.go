@@ -0,0 +1,268 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package names parses and formats the resource names of the DT API, such as
+// projects/{project}/devices/{device}.
+//
+// Each kind of resource has a name type with a Parse function, which checks
+// the collections of the name and the charset of its IDs, and a String method,
+// which formats the name.
+package names
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// idPattern matches the IDs in resource names. The IDs generated by the API
+// are letters and digits, and role IDs such as project.developer have dots.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*$`)
+
+// parse splits a name in the format of pattern, such as
+// projects/{project}/devices/{device}, into its IDs. It returns an error if
+// the collections of the name are not the ones of the pattern, or if an ID is
+// invalid.
+func parse(kind, pattern, name string) ([]string, error) {
+	want := strings.Split(pattern, "/")
+	segments := strings.Split(name, "/")
+	if len(segments) != len(want) {
+		return nil, fmt.Errorf("names: invalid %s name %q: want the format %s", kind, name, pattern)
+	}
+	ids := make([]string, 0, len(segments)/2)
+	for i := 0; i < len(segments); i += 2 {
+		if segments[i] != want[i] {
+			return nil, fmt.Errorf("names: invalid %s name %q: want %q instead of %q, in the format %s", kind, name, want[i], segments[i], pattern)
+		}
+		if !idPattern.MatchString(segments[i+1]) {
+			return nil, fmt.Errorf("names: invalid %s name %q: %q is not a valid ID", kind, name, segments[i+1])
+		}
+		ids = append(ids, segments[i+1])
+	}
+	return ids, nil
+}
+
+// OrganizationName is the name of an organization, organizations/{organization}.
+type OrganizationName struct {
+	Organization string
+}
+
+// ParseOrganizationName parses the name of an organization.
+func ParseOrganizationName(name string) (OrganizationName, error) {
+	ids, err := parse("organization", "organizations/{organization}", name)
+	if err != nil {
+		return OrganizationName{}, err
+	}
+	return OrganizationName{Organization: ids[0]}, nil
+}
+
+func (n OrganizationName) String() string {
+	return "organizations/" + n.Organization
+}
+
+// ProjectName is the name of a project, projects/{project}.
+type ProjectName struct {
+	Project string
+}
+
+// ParseProjectName parses the name of a project.
+func ParseProjectName(name string) (ProjectName, error) {
+	ids, err := parse("project", "projects/{project}", name)
+	if err != nil {
+		return ProjectName{}, err
+	}
+	return ProjectName{Project: ids[0]}, nil
+}
+
+func (n ProjectName) String() string {
+	return "projects/" + n.Project
+}
+
+// DeviceName is the name of a device, projects/{project}/devices/{device}.
+// Emulated devices have names in the same format.
+type DeviceName struct {
+	Project string
+	Device  string
+}
+
+// ParseDeviceName parses the name of a device.
+func ParseDeviceName(name string) (DeviceName, error) {
+	ids, err := parse("device", "projects/{project}/devices/{device}", name)
+	if err != nil {
+		return DeviceName{}, err
+	}
+	return DeviceName{Project: ids[0], Device: ids[1]}, nil
+}
+
+func (n DeviceName) String() string {
+	return "projects/" + n.Project + "/devices/" + n.Device
+}
+
+// ProjectName returns the name of the project of the device.
+func (n DeviceName) ProjectName() ProjectName {
+	return ProjectName{Project: n.Project}
+}
+
+// RuleName is the name of a notification rule, which belongs to either a
+// project, projects/{project}/rules/{rule}, or an organization,
+// organizations/{organization}/rules/{rule}. Only one of Project and
+// Organization is set.
+type RuleName struct {
+	Project      string
+	Organization string
+	Rule         string
+}
+
+// ParseRuleName parses the name of a notification rule.
+func ParseRuleName(name string) (RuleName, error) {
+	if strings.HasPrefix(name, "organizations/") {
+		ids, err := parse("notification rule", "organizations/{organization}/rules/{rule}", name)
+		if err != nil {
+			return RuleName{}, err
+		}
+		return RuleName{Organization: ids[0], Rule: ids[1]}, nil
+	}
+	ids, err := parse("notification rule", "projects/{project}/rules/{rule}", name)
+	if err != nil {
+		return RuleName{}, err
+	}
+	return RuleName{Project: ids[0], Rule: ids[1]}, nil
+}
+
+func (n RuleName) String() string {
+	return n.Parent() + "/rules/" + n.Rule
+}
+
+// Parent returns the name of the project or organization of the rule.
+func (n RuleName) Parent() string {
+	if n.Organization != "" {
+		return OrganizationName{Organization: n.Organization}.String()
+	}
+	return ProjectName{Project: n.Project}.String()
+}
+
+// DataConnectorName is the name of a data connector,
+// projects/{project}/dataconnectors/{data_connector}.
+type DataConnectorName struct {
+	Project       string
+	DataConnector string
+}
+
+// ParseDataConnectorName parses the name of a data connector.
+func ParseDataConnectorName(name string) (DataConnectorName, error) {
+	ids, err := parse("data connector", "projects/{project}/dataconnectors/{data_connector}", name)
+	if err != nil {
+		return DataConnectorName{}, err
+	}
+	return DataConnectorName{Project: ids[0], DataConnector: ids[1]}, nil
+}
+
+func (n DataConnectorName) String() string {
+	return "projects/" + n.Project + "/dataconnectors/" + n.DataConnector
+}
+
+// ContactName is the name of a contact, projects/{project}/contacts/{contact}.
+type ContactName struct {
+	Project string
+	Contact string
+}
+
+// ParseContactName parses the name of a contact.
+func ParseContactName(name string) (ContactName, error) {
+	ids, err := parse("contact", "projects/{project}/contacts/{contact}", name)
+	if err != nil {
+		return ContactName{}, err
+	}
+	return ContactName{Project: ids[0], Contact: ids[1]}, nil
+}
+
+func (n ContactName) String() string {
+	return "projects/" + n.Project + "/contacts/" + n.Contact
+}
+
+// ProjectName returns the name of the project of the contact.
+func (n ContactName) ProjectName() ProjectName {
+	return ProjectName{Project: n.Project}
+}
+
+// ContactGroupName is the name of a contact group,
+// organizations/{organization}/contactGroups/{contact_group}.
+type ContactGroupName struct {
+	Organization string
+	ContactGroup string
+}
+
+// ParseContactGroupName parses the name of a contact group.
+func ParseContactGroupName(name string) (ContactGroupName, error) {
+	ids, err := parse("contact group", "organizations/{organization}/contactGroups/{contact_group}", name)
+	if err != nil {
+		return ContactGroupName{}, err
+	}
+	return ContactGroupName{Organization: ids[0], ContactGroup: ids[1]}, nil
+}
+
+func (n ContactGroupName) String() string {
+	return "organizations/" + n.Organization + "/contactGroups/" + n.ContactGroup
+}
+
+// OrganizationName returns the name of the organization of the contact group.
+func (n ContactGroupName) OrganizationName() OrganizationName {
+	return OrganizationName{Organization: n.Organization}
+}
+
+// MemberName is the name of a project membership,
+// projects/{project}/members/{member}.
+type MemberName struct {
+	Project string
+	Member  string
+}
+
+// ParseMemberName parses the name of a project membership.
+func ParseMemberName(name string) (MemberName, error) {
+	ids, err := parse("member", "projects/{project}/members/{member}", name)
+	if err != nil {
+		return MemberName{}, err
+	}
+	return MemberName{Project: ids[0], Member: ids[1]}, nil
+}
+
+func (n MemberName) String() string {
+	return "projects/" + n.Project + "/members/" + n.Member
+}
+
+// ProjectName returns the name of the project of the membership.
+func (n MemberName) ProjectName() ProjectName {
+	return ProjectName{Project: n.Project}
+}
+
+// RoleBindingName is the name the provider gives the role of a member in the
+// projects of an organization,
+// organizations/{organization}/roles/{role}/members/{member}. It is not a name
+// of the API.
+type RoleBindingName struct {
+	Organization string
+	Role         string
+	Member       string
+}
+
+// ParseRoleBindingName parses the name of a role binding.
+func ParseRoleBindingName(name string) (RoleBindingName, error) {
+	ids, err := parse("role binding", "organizations/{organization}/roles/{role}/members/{member}", name)
+	if err != nil {
+		return RoleBindingName{}, err
+	}
+	return RoleBindingName{Organization: ids[0], Role: ids[1], Member: ids[2]}, nil
+}
+
+func (n RoleBindingName) String() string {
+	return "organizations/" + n.Organization + "/roles/" + n.Role + "/members/" + n.Member
+}
+
+// OrganizationName returns the name of the organization of the role binding.
+func (n RoleBindingName) OrganizationName() OrganizationName {
+	return OrganizationName{Organization: n.Organization}
+}
+
+// RoleName returns the name of the role, roles/{role}.
+func (n RoleBindingName) RoleName() string {
+	return "roles/" + n.Role
+}
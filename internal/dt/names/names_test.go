@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+
+package names_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+)
+
+// parser parses a name and returns it as a fmt.Stringer.
+type parser func(string) (fmt.Stringer, error)
+
+func parserOf[N fmt.Stringer](parse func(string) (N, error)) parser {
+	return func(name string) (fmt.Stringer, error) {
+		return parse(name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		parse   parser
+		input   string
+		want    fmt.Stringer
+		wantErr string
+	}{
+		{
+			name:  "organization",
+			parse: parserOf(names.ParseOrganizationName),
+			input: "organizations/cvinmt9aq9sc738g6eog",
+			want:  names.OrganizationName{Organization: "cvinmt9aq9sc738g6eog"},
+		},
+		{
+			name:  "project",
+			parse: parserOf(names.ParseProjectName),
+			input: "projects/cvinutal2ugc73b866v0",
+			want:  names.ProjectName{Project: "cvinutal2ugc73b866v0"},
+		},
+		{
+			name:  "device",
+			parse: parserOf(names.ParseDeviceName),
+			input: "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg",
+			want:  names.DeviceName{Project: "cvinutal2ugc73b866v0", Device: "emucvio050h6oic7398hljg"},
+		},
+		{
+			name:  "project rule",
+			parse: parserOf(names.ParseRuleName),
+			input: "projects/p/rules/r",
+			want:  names.RuleName{Project: "p", Rule: "r"},
+		},
+		{
+			name:  "organization rule",
+			parse: parserOf(names.ParseRuleName),
+			input: "organizations/o/rules/r",
+			want:  names.RuleName{Organization: "o", Rule: "r"},
+		},
+		{
+			name:  "data connector",
+			parse: parserOf(names.ParseDataConnectorName),
+			input: "projects/p/dataconnectors/d",
+			want:  names.DataConnectorName{Project: "p", DataConnector: "d"},
+		},
+		{
+			name:  "contact",
+			parse: parserOf(names.ParseContactName),
+			input: "projects/p/contacts/c",
+			want:  names.ContactName{Project: "p", Contact: "c"},
+		},
+		{
+			name:  "contact group",
+			parse: parserOf(names.ParseContactGroupName),
+			input: "organizations/o/contactGroups/g",
+			want:  names.ContactGroupName{Organization: "o", ContactGroup: "g"},
+		},
+		{
+			name:  "member",
+			parse: parserOf(names.ParseMemberName),
+			input: "projects/p/members/123",
+			want:  names.MemberName{Project: "p", Member: "123"},
+		},
+		{
+			name:  "role binding",
+			parse: parserOf(names.ParseRoleBindingName),
+			input: "organizations/o/roles/project.user/members/123",
+			want:  names.RoleBindingName{Organization: "o", Role: "project.user", Member: "123"},
+		},
+		{
+			name:    "empty",
+			parse:   parserOf(names.ParseProjectName),
+			input:   "",
+			wantErr: `names: invalid project name "": want the format projects/{project}`,
+		},
+		{
+			name:    "ID without collection",
+			parse:   parserOf(names.ParseProjectName),
+			input:   "cvinutal2ugc73b866v0",
+			wantErr: `want the format projects/{project}`,
+		},
+		{
+			name:    "too many segments",
+			parse:   parserOf(names.ParseDeviceName),
+			input:   "projects/p/devices/d/labels/l",
+			wantErr: `want the format projects/{project}/devices/{device}`,
+		},
+		{
+			name:    "wrong collection",
+			parse:   parserOf(names.ParseDeviceName),
+			input:   "projects/p/dataconnectors/d",
+			wantErr: `want "devices" instead of "dataconnectors"`,
+		},
+		{
+			name:    "wrong parent collection",
+			parse:   parserOf(names.ParseDataConnectorName),
+			input:   "organizations/o/dataconnectors/d",
+			wantErr: `want "projects" instead of "organizations"`,
+		},
+		{
+			name:    "rule of a device",
+			parse:   parserOf(names.ParseRuleName),
+			input:   "devices/d/rules/r",
+			wantErr: `want "projects" instead of "devices"`,
+		},
+		{
+			name:    "empty ID",
+			parse:   parserOf(names.ParseContactName),
+			input:   "projects/p/contacts/",
+			wantErr: `"" is not a valid ID`,
+		},
+		{
+			name:    "invalid ID",
+			parse:   parserOf(names.ParseProjectName),
+			input:   "projects/my project",
+			wantErr: `"my project" is not a valid ID`,
+		},
+		{
+			name:    "relative ID",
+			parse:   parserOf(names.ParseMemberName),
+			input:   "projects/p/members/..",
+			wantErr: `".." is not a valid ID`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.parse(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parse(%q) error = %v, want an error containing %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parse(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+			if got.String() != tt.input {
+				t.Errorf("parse(%q).String() = %q, want the input", tt.input, got.String())
+			}
+		})
+	}
+}
@@ -6,15 +6,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 	"strings"
-	"sync"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 )
 
 // DISCLAIMER: The Notification Rule API is not released yet and is subject to change.
 
 type ListNotificationRuleResponse struct {
 	NotificationRules []NotificationRule `json:"rules"`
+	NextPageToken     string             `json:"nextPageToken"`
 }
 
 // NotificationRule represents a notification rule in the Disruptive Technologies platform.
@@ -149,72 +152,66 @@ type TimeOfDay struct {
 	Minute int32 `json:"minute"`
 }
 
-type rulesCache struct {
-	notificationRules map[string]NotificationRule
-
-	mu sync.RWMutex
-}
-
-func (c *rulesCache) getRule(rule string) (NotificationRule, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if rule, ok := c.notificationRules[rule]; ok {
-		return rule, true
-	}
-	return NotificationRule{}, false
-}
-
-func (c *rulesCache) setRule(rule NotificationRule) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.notificationRules[rule.Name] = rule
-}
-
-// GetNotificationRule returns a notification rule by resource name.
+// GetNotificationRule returns a notification rule by resource name. Unless
+// caching is disabled, all the rules of the parent are listed and cached on
+// the first call, so that reading many rules only takes a single list request.
 func (c *Client) GetNotificationRule(ctx context.Context, name string) (NotificationRule, error) {
 	// Try to get the rule from the cache first:
-	if rule, ok := c.rulesCache.getRule(name); ok {
+	if rule, ok := c.rulesCache.get(name); ok {
 		return rule, nil
 	}
 
 	// If the rule is not in the cache, we need to parse the resource name
-	parentType, parentID, _, err := ParseRuleResourceName(name)
+	ruleName, err := names.ParseRuleName(name)
 	if err != nil {
 		return NotificationRule{}, fmt.Errorf("dt: failed to parse resource name: %w", err)
 	}
-	parent := fmt.Sprintf("%s/%s", parentType, parentID)
+	parent := ruleName.Parent()
+
+	// Get the rule directly if there is no cache to populate.
+	if c.rulesCache.disabled {
+		url := fmt.Sprintf("%s/v2alpha/%s", strings.TrimSuffix(c.URL, "/"), ruleName)
+		responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, nil)
+		if err != nil {
+			return NotificationRule{}, fmt.Errorf("dt: failed to get notification rule: %w", err)
+		}
+		var rule NotificationRule
+		if err := json.Unmarshal(responseBody, &rule); err != nil {
+			return NotificationRule{}, fmt.Errorf("dt: failed to unmarshal notification rule: %w", err)
+		}
+		return rule, nil
+	}
 
 	// make a list request to get all rules in the project and populate the cache.
-	response, err := c.listNotificationRules(ctx, parent)
-	if err != nil {
-		return NotificationRule{}, fmt.Errorf("dt: failed to list notification rules: %w", err)
-	}
-	for _, rule := range response.NotificationRules {
-		c.rulesCache.setRule(rule)
+	var found bool
+	var rule NotificationRule
+	for r, err := range c.ListNotificationRules(ctx, parent) {
+		if err != nil {
+			return NotificationRule{}, fmt.Errorf("dt: failed to list notification rules: %w", err)
+		}
+		c.rulesCache.set(r.Name, r)
+		if r.Name == name {
+			rule, found = r, true
+		}
 	}
-
-	// Now that the cache is populated, we can get the rule by name
-	rule, ok := c.rulesCache.getRule(name)
-	if !ok {
-		return NotificationRule{}, fmt.Errorf("dt: notification rule not found: %s", name)
+	if !found {
+		return NotificationRule{}, fmt.Errorf("dt: notification rule %w: %s", ErrNotFound, name)
 	}
 
 	return rule, nil
 }
 
-func (c *Client) listNotificationRules(ctx context.Context, parent string) (ListNotificationRuleResponse, error) {
+// ListNotificationRules returns an iterator over the notification rules of
+// the parent, which is either a project or an organization.
+func (c *Client) ListNotificationRules(ctx context.Context, parent string) iter.Seq2[NotificationRule, error] {
 	url := fmt.Sprintf("%s/v2alpha/%s/rules", strings.TrimSuffix(c.URL, "/"), parent)
-	responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, nil)
-	if err != nil {
-		return ListNotificationRuleResponse{}, fmt.Errorf("dt: failed to list notification rules: %w", err)
-	}
-
-	var rules ListNotificationRuleResponse
-	if err := json.Unmarshal(responseBody, &rules); err != nil {
-		return ListNotificationRuleResponse{}, fmt.Errorf("dt: failed to unmarshal notification rules: %w", err)
-	}
-
-	return rules, nil
+	return paginate(ctx, c, url, nil, func(body []byte) ([]NotificationRule, string, error) {
+		var rules ListNotificationRuleResponse
+		if err := json.Unmarshal(body, &rules); err != nil {
+			return nil, "", fmt.Errorf("dt: failed to unmarshal notification rules: %w", err)
+		}
+		return rules.NotificationRules, rules.NextPageToken, nil
+	})
 }
 
 // CreateNotificationRule creates a new notification rule.
@@ -235,18 +232,19 @@ func (c *Client) CreateNotificationRule(ctx context.Context, parent string, rule
 	if err := json.Unmarshal(responseBody, &createdRule); err != nil {
 		return NotificationRule{}, fmt.Errorf("dt: failed to unmarshal created notification rule: %w", err)
 	}
+	c.rulesCache.set(createdRule.Name, createdRule)
 
 	return createdRule, nil
 }
 
 // UpdateNotificationRule updates an existing notification rule.
 func (c *Client) UpdateNotificationRule(ctx context.Context, rule NotificationRule) (NotificationRule, error) {
-	parentType, parentID, ruleID, err := ParseRuleResourceName(rule.Name)
+	ruleName, err := names.ParseRuleName(rule.Name)
 	if err != nil {
 		return NotificationRule{}, fmt.Errorf("dt: failed to parse resource name: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/v2alpha/%s/%s/rules/%s", strings.TrimSuffix(c.URL, "/"), parentType, parentID, ruleID)
+	url := fmt.Sprintf("%s/v2alpha/%s", strings.TrimSuffix(c.URL, "/"), ruleName)
 
 	body, err := json.Marshal(rule)
 	if err != nil {
@@ -262,40 +260,24 @@ func (c *Client) UpdateNotificationRule(ctx context.Context, rule NotificationRu
 	if err := json.Unmarshal(responseBody, &updatedRule); err != nil {
 		return NotificationRule{}, fmt.Errorf("dt: failed to unmarshal updated notification rule: %w", err)
 	}
+	c.rulesCache.set(updatedRule.Name, updatedRule)
 
 	return updatedRule, nil
 }
 
 // DeleteNotificationRule deletes a notification rule.
 func (c *Client) DeleteNotificationRule(ctx context.Context, name string) error {
-	parentType, projectID, ruleID, err := ParseRuleResourceName(name)
+	ruleName, err := names.ParseRuleName(name)
 	if err != nil {
 		return fmt.Errorf("dt: failed to parse resource name: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/v2alpha/%s/%s/rules/%s", strings.TrimSuffix(c.URL, "/"), parentType, projectID, ruleID)
+	url := fmt.Sprintf("%s/v2alpha/%s", strings.TrimSuffix(c.URL, "/"), ruleName)
 	_, err = c.DoRequest(ctx, http.MethodDelete, url, nil, nil)
 	if err != nil {
 		return fmt.Errorf("dt: failed to delete notification rule: %w", err)
 	}
+	c.rulesCache.delete(name)
 
 	return nil
 }
-
-func ParseRuleResourceName(name string) (string, string, string, error) {
-	parts := strings.Split(name, "/")
-	if len(parts) != 4 {
-		return "", "", "", fmt.Errorf("dt: invalid resource name: %s", name)
-	}
-
-	// If the resource parent is not project or organization, we return an error.
-	if parts[0] != "projects" && parts[0] != "organizations" {
-		return "", "", "", fmt.Errorf("dt: invalid resource name: %s", name)
-	}
-
-	// If the resource type is not rule, we return an error.
-	if parts[2] != "rules" {
-		return "", "", "", fmt.Errorf("dt: invalid resource name: %s", name)
-	}
-	return parts[0], parts[1], parts[3], nil
-}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/filelock"
+)
+
+// tokenCache is an on-disk cache of the access token of a service account
+// key, which lets the provider processes that Terraform starts for validate,
+// plan and apply share a token instead of each requesting one.
+//
+// The cache file is locked while a token is read and refreshed, so that only
+// one process requests a new token when the cached one has expired. The files
+// are only readable by their owner.
+type tokenCache struct {
+	// The path of the cache file.
+	path string
+}
+
+// cachedToken is the format of the token cache files.
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	Expiry      time.Time `json:"expiry"`
+}
+
+// newTokenCache returns a cache in dir for the tokens of the key with the given
+// ID at the token endpoint. It returns nil if dir is empty.
+func newTokenCache(dir, clientID, tokenEndpoint string) *tokenCache {
+	if dir == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(clientID + "\n" + tokenEndpoint))
+	return &tokenCache{path: filepath.Join(dir, hex.EncodeToString(sum[:])+".json")}
+}
+
+// lock takes an exclusive lock on the cache file, waiting until other
+// processes release it or ctx is done. The returned function releases the lock.
+func (c *tokenCache) lock(ctx context.Context) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return nil, fmt.Errorf("oidc: failed to create token cache directory: %w", err)
+	}
+	unlock, err := filelock.Lock(ctx, c.path+".lock")
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to lock token cache: %w", err)
+	}
+	return unlock, nil
+}
+
+// load returns the cached token, if there is one that is valid for at least
+// minLifetime. The lock must be held.
+func (c *tokenCache) load(minLifetime time.Duration) (cachedToken, bool, error) {
+	f, err := os.Open(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cachedToken{}, false, nil
+	}
+	if err != nil {
+		return cachedToken{}, false, fmt.Errorf("oidc: failed to open token cache: %w", err)
+	}
+	defer f.Close()
+
+	// Ignore files that others can read, they might have been tampered with.
+	info, err := f.Stat()
+	if err != nil {
+		return cachedToken{}, false, fmt.Errorf("oidc: failed to stat token cache: %w", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return cachedToken{}, false, fmt.Errorf("oidc: token cache %s is accessible by other users", c.path)
+	}
+
+	var token cachedToken
+	if err := json.NewDecoder(f).Decode(&token); err != nil {
+		return cachedToken{}, false, fmt.Errorf("oidc: failed to decode token cache: %w", err)
+	}
+	if token.AccessToken == "" || time.Until(token.Expiry) < minLifetime {
+		return cachedToken{}, false, nil
+	}
+	return token, true, nil
+}
+
+// store replaces the cached token. The lock must be held.
+func (c *tokenCache) store(token cachedToken) error {
+	// Write to a temporary file and rename it, so that the cache file is never
+	// partially written. CreateTemp creates files only readable by their owner.
+	f, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("oidc: failed to create token cache: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if err := json.NewEncoder(f).Encode(token); err != nil {
+		f.Close()
+		return fmt.Errorf("oidc: failed to write token cache: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("oidc: failed to write token cache: %w", err)
+	}
+	if err := os.Rename(f.Name(), c.path); err != nil {
+		return fmt.Errorf("oidc: failed to write token cache: %w", err)
+	}
+	return nil
+}
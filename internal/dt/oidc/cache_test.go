@@ -0,0 +1,149 @@
+// Copyright (c) HashiCorp, Inc.
+
+package oidc_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+)
+
+// newTokenServer returns a token endpoint that issues tokens valid for
+// expiresIn seconds, and a counter of the token requests it has received.
+func newTokenServer(t *testing.T, expiresIn int) (string, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		// Give concurrent callers time to pile up.
+		time.Sleep(20 * time.Millisecond)
+		_ = json.NewEncoder(w).Encode(oidc.AuthResponse{
+			AccessToken: fmt.Sprintf("token-%d", n),
+			TokenType:   "Bearer",
+			ExpiresIn:   expiresIn,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &requests
+}
+
+func newTestClient(tokenEndpoint, tokenCacheDir string) *oidc.Client {
+	return oidc.NewClient(oidc.Config{
+		TokenEndpoint: tokenEndpoint,
+		ClientID:      "key-id",
+		ClientSecret:  "key-secret",
+		Email:         "sa@example.com",
+		TokenCacheDir: tokenCacheDir,
+	})
+}
+
+func TestGetTokenDeduplicatesConcurrentRequests(t *testing.T) {
+	t.Parallel()
+	tokenEndpoint, requests := newTokenServer(t, 3600)
+	client := newTestClient(tokenEndpoint, "")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetToken(context.Background()); err != nil {
+				t.Errorf("GetToken() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := requests.Load(); got != 1 {
+		t.Errorf("10 concurrent GetToken() calls sent %d token requests, want 1", got)
+	}
+}
+
+func TestTokenCacheIsSharedBetweenClients(t *testing.T) {
+	t.Parallel()
+	tokenEndpoint, requests := newTokenServer(t, 3600)
+	dir := filepath.Join(t.TempDir(), "tokens")
+
+	// Each client stands in for a separate provider process.
+	var wg sync.WaitGroup
+	tokens := make([]string, 5)
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := newTestClient(tokenEndpoint, dir).GetToken(context.Background())
+			if err != nil {
+				t.Errorf("GetToken() error = %v", err)
+				return
+			}
+			tokens[i] = token.AccessToken
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("5 clients sharing a token cache sent %d token requests, want 1", got)
+	}
+	for i, token := range tokens {
+		if token != "token-1" {
+			t.Errorf("client %d got token %q, want %q", i, token, "token-1")
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("token cache files = %v, %v, want one file", files, err)
+	}
+	info, err := os.Stat(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("token cache file permissions = %v, want %v", perm, os.FileMode(0o600))
+	}
+}
+
+func TestTokenCacheRefreshesTokensBeforeTheyExpire(t *testing.T) {
+	t.Parallel()
+	// Tokens valid for one minute are too close to expiring to be reused.
+	tokenEndpoint, requests := newTokenServer(t, 60)
+	dir := t.TempDir()
+
+	for range 2 {
+		if _, err := newTestClient(tokenEndpoint, dir).GetToken(context.Background()); err != nil {
+			t.Fatalf("GetToken() error = %v", err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("sent %d token requests, want 2", got)
+	}
+}
+
+func TestTokenCacheIsKeyedByTokenEndpoint(t *testing.T) {
+	t.Parallel()
+	first, firstRequests := newTokenServer(t, 3600)
+	second, secondRequests := newTokenServer(t, 3600)
+	dir := t.TempDir()
+
+	for _, tokenEndpoint := range []string{first, second} {
+		if _, err := newTestClient(tokenEndpoint, dir).GetToken(context.Background()); err != nil {
+			t.Fatalf("GetToken() error = %v", err)
+		}
+	}
+	if firstRequests.Load() != 1 || secondRequests.Load() != 1 {
+		t.Errorf("token requests = %d and %d, want 1 to each token endpoint", firstRequests.Load(), secondRequests.Load())
+	}
+}
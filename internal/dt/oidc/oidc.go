@@ -3,6 +3,7 @@
 package oidc
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,10 +12,21 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/redact"
 	jwt "github.com/golang-jwt/jwt/v5"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultRefreshMargin is how long before they expire tokens are refreshed
+	// when Config.RefreshMargin is not set.
+	DefaultRefreshMargin = 5 * time.Minute
+	// DefaultTimeout is the timeout of token requests when Config.Timeout is not set.
+	DefaultTimeout = 10 * time.Second
 )
 
 type Client struct {
@@ -26,9 +38,21 @@ type Client struct {
 	clientSecret string
 	// The email address used to authenticate with the OIDC provider.
 	email string
+	// Whether to log secrets, such as the JWT assertion and the access token.
+	unsafeLogging bool
+	// How long before it expires the token is refreshed.
+	refreshMargin time.Duration
+	// The HTTP client used for token requests.
+	httpClient *http.Client
 
 	// The access token used to access the Disruptive REST API.
 	token *Token
+	// The on-disk token cache shared with other processes, or nil if it is disabled.
+	tokenCache *tokenCache
+	// Deduplicates concurrent requests for a new token.
+	group singleflight.Group
+	// The number of tokens requested from the token endpoint.
+	fetches atomic.Int64
 }
 
 type Token struct {
@@ -36,13 +60,10 @@ type Token struct {
 	accessToken string
 	tokenType   string
 	expiry      time.Time
-	mu          sync.RWMutex
-}
-
-func (t *Token) get() string {
-	t.mu.RLock()
-	defer t.mu.RUnlock()
-	return t.accessToken
+	// The last access token that was rejected by the API, which must not be
+	// read back from the token cache.
+	rejected string
+	mu       sync.RWMutex
 }
 
 func (t *Token) set(token, tokenType string, expiry time.Time) {
@@ -53,10 +74,36 @@ func (t *Token) set(token, tokenType string, expiry time.Time) {
 	t.tokenType = tokenType
 }
 
-func (t *Token) isExpired() bool {
+// invalidate forgets the access token if it is the current token.
+func (t *Token) invalidate(accessToken string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rejected = accessToken
+	if t.accessToken == accessToken {
+		t.accessToken = ""
+	}
+}
+
+// isRejected returns whether the access token was rejected by the API.
+func (t *Token) isRejected(accessToken string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.rejected == accessToken
+}
+
+// response returns the token as an AuthResponse, or false if there is no
+// token that is valid for at least margin.
+func (t *Token) response(margin time.Duration) (*AuthResponse, bool) {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	return t.expiry.Before(time.Now())
+	if t.accessToken == "" || time.Until(t.expiry) < margin {
+		return nil, false
+	}
+	return &AuthResponse{
+		AccessToken: t.accessToken,
+		TokenType:   t.tokenType,
+		ExpiresIn:   int(time.Until(t.expiry).Seconds()),
+	}, true
 }
 
 type Config struct {
@@ -68,15 +115,42 @@ type Config struct {
 	ClientSecret string
 	// The email address used to authenticate with the OIDC provider.
 	Email string
+	// UnsafeLogging disables the masking of secrets in the debug logs. It is
+	// only meant for debugging locally.
+	UnsafeLogging bool
+	// TokenCacheDir is the directory of the on-disk token cache, which lets
+	// processes share access tokens. The cache is disabled if it is empty.
+	TokenCacheDir string
+	// RefreshMargin is how long before they expire tokens are refreshed, so
+	// that they do not expire during requests or because of clock skew. It
+	// defaults to DefaultRefreshMargin.
+	RefreshMargin time.Duration
+	// Timeout is the timeout of token requests. It defaults to DefaultTimeout.
+	Timeout time.Duration
+	// HTTPClient is the HTTP client used for token requests, such as one
+	// configured with a proxy. Its timeout is replaced by Timeout. It defaults
+	// to a client with the default transport.
+	HTTPClient *http.Client
 }
 
 func NewClient(cfg Config) *Client {
+	httpClient := &http.Client{}
+	if cfg.HTTPClient != nil {
+		clone := *cfg.HTTPClient
+		httpClient = &clone
+	}
+	httpClient.Timeout = cmp.Or(cfg.Timeout, DefaultTimeout)
+
 	return &Client{
 		tokenEndpoint: cfg.TokenEndpoint,
 		clientID:      cfg.ClientID,
 		clientSecret:  cfg.ClientSecret,
 		email:         cfg.Email,
+		unsafeLogging: cfg.UnsafeLogging,
+		refreshMargin: cmp.Or(cfg.RefreshMargin, DefaultRefreshMargin),
+		httpClient:    httpClient,
 		token:         &Token{},
+		tokenCache:    newTokenCache(cfg.TokenCacheDir, cfg.ClientID, cfg.TokenEndpoint),
 	}
 }
 
@@ -108,16 +182,103 @@ func (c *Client) createJWT() (string, error) {
 }
 
 func (c *Client) GetToken(ctx context.Context) (*AuthResponse, error) {
+	ctx = redact.Context(ctx, c.unsafeLogging, c.clientSecret)
+
 	// Check if we already have a valid cached token, if so, return it.
-	if cachedToken := c.token.get(); cachedToken != "" && !c.token.isExpired() {
+	if token, ok := c.token.response(c.refreshMargin); ok {
 		tflog.Debug(ctx, "using cached token")
+		return token, nil
+	}
+
+	return c.refresh(ctx)
+}
+
+// InvalidateToken forgets the access token after the API rejected it, so that
+// the next call to GetToken requests a new token. It does nothing if the token
+// has already been replaced.
+func (c *Client) InvalidateToken(accessToken string) {
+	c.token.invalidate(accessToken)
+}
+
+// refresh returns a new token. Concurrent callers share the same new token.
+func (c *Client) refresh(ctx context.Context) (*AuthResponse, error) {
+	// Concurrent callers wait for the same new token instead of each requesting one.
+	result := c.group.DoChan("token", func() (interface{}, error) {
+		// The token might have been refreshed while this caller waited.
+		if token, ok := c.token.response(c.refreshMargin); ok {
+			return token, nil
+		}
+		// The refresh is shared by every waiting caller, so it must not fail
+		// when the caller that started it is cancelled. The token timeout
+		// bounds it instead.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.httpClient.Timeout)
+		defer cancel()
+		return c.refreshToken(ctx)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("oidc: failed to get token: %w", ctx.Err())
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*AuthResponse), nil
+	}
+}
+
+// refreshToken reads the token from the on-disk token cache, and requests a
+// new token if the cached one is missing or about to expire.
+func (c *Client) refreshToken(ctx context.Context) (*AuthResponse, error) {
+	if c.tokenCache == nil {
+		return c.requestToken(ctx)
+	}
+	ctx = tflog.SetField(ctx, "token_cache", c.tokenCache.path)
+
+	unlock, err := c.tokenCache.lock(ctx)
+	if err != nil {
+		tflog.Warn(ctx, "failed to lock the token cache, requesting a new token", map[string]interface{}{"error": err.Error()})
+		return c.requestToken(ctx)
+	}
+	defer unlock()
+
+	cached, ok, err := c.tokenCache.load(c.refreshMargin)
+	if err != nil {
+		tflog.Warn(ctx, "failed to read the token cache, requesting a new token", map[string]interface{}{"error": err.Error()})
+	}
+	if ok && !c.token.isRejected(cached.AccessToken) {
+		tflog.Debug(ctx, "using token from the token cache")
+		c.token.set(cached.AccessToken, cached.TokenType, cached.Expiry)
 		return &AuthResponse{
-			AccessToken: cachedToken,
-			TokenType:   c.token.tokenType,
-			ExpiresIn:   int(time.Until(c.token.expiry).Seconds()),
+			AccessToken: cached.AccessToken,
+			TokenType:   cached.TokenType,
+			ExpiresIn:   int(time.Until(cached.Expiry).Seconds()),
 		}, nil
 	}
 
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cached = cachedToken{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}
+	if err := c.tokenCache.store(cached); err != nil {
+		tflog.Warn(ctx, "failed to write the token cache", map[string]interface{}{"error": err.Error()})
+	}
+	return token, nil
+}
+
+// TokenFetches returns the number of access tokens the client has requested
+// from the token endpoint, not counting tokens read from the token cache.
+func (c *Client) TokenFetches() int {
+	return int(c.fetches.Load())
+}
+
+// requestToken exchanges a new JWT for an access token at the token endpoint.
+func (c *Client) requestToken(ctx context.Context) (*AuthResponse, error) {
+	c.fetches.Add(1)
 	jwt, err := c.createJWT()
 	if err != nil {
 		return nil, fmt.Errorf("oidc: failed to create JWT: %w", err)
@@ -145,10 +306,8 @@ func (c *Client) GetToken(ctx context.Context) (*AuthResponse, error) {
 	// Set Content-Type header to specify that our body is Form-URL Encoded.
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	// Exchange the JWT for an access token. Set a 3 second
-	// timeout in case the server can't be reached.
-	httpClient := &http.Client{Timeout: time.Second * 3}
-	response, err := httpClient.Do(req)
+	// Exchange the JWT for an access token.
+	response, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("oidc: failed to send request: %w", err)
 	}
@@ -162,7 +321,7 @@ func (c *Client) GetToken(ctx context.Context) (*AuthResponse, error) {
 	ctx = tflog.SetField(ctx, "status_code", response.StatusCode)
 	if response.StatusCode != http.StatusOK {
 		tflog.Debug(ctx, "received non-200 status code from DT API")
-		return nil, fmt.Errorf("HTTP error: %d: %s", response.StatusCode, string(bodyBytes))
+		return nil, &HTTPError{StatusCode: response.StatusCode, Body: string(bodyBytes)}
 	}
 
 	// Decode the response body to an AuthResponse.
@@ -180,6 +339,16 @@ func (c *Client) GetToken(ctx context.Context) (*AuthResponse, error) {
 
 }
 
+// HTTPError is returned when the token endpoint responds with a status code other than 200 OK.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error: %d: %s", e.StatusCode, e.Body)
+}
+
 type AuthResponse struct {
 	// The access token used to access the Disruptive REST API.
 	AccessToken string `json:"access_token"`
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+
+package oidc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+)
+
+func TestGetTokenRefreshesTokensBeforeTheyExpire(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		margin       time.Duration
+		wantRequests int32
+	}{
+		// Tokens valid for a minute are within the default margin of five minutes.
+		{name: "default margin", wantRequests: 2},
+		{name: "short margin", margin: 30 * time.Second, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tokenEndpoint, requests := newTokenServer(t, 60)
+			client := oidc.NewClient(oidc.Config{
+				TokenEndpoint: tokenEndpoint,
+				ClientID:      "key-id",
+				ClientSecret:  "key-secret",
+				Email:         "sa@example.com",
+				RefreshMargin: tt.margin,
+			})
+
+			for range 2 {
+				if _, err := client.GetToken(context.Background()); err != nil {
+					t.Fatalf("GetToken() error = %v", err)
+				}
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("sent %d token requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestInvalidateToken(t *testing.T) {
+	t.Parallel()
+	tokenEndpoint, requests := newTokenServer(t, 3600)
+	client := newTestClient(tokenEndpoint, t.TempDir())
+	ctx := context.Background()
+
+	first, err := client.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	client.InvalidateToken(first.AccessToken)
+	second, err := client.GetToken(ctx)
+	if err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if second.AccessToken == first.AccessToken {
+		t.Errorf("GetToken() after InvalidateToken() returned the rejected token %q", first.AccessToken)
+	}
+
+	// Invalidating a token that has already been replaced keeps the current token.
+	client.InvalidateToken(first.AccessToken)
+	if _, err := client.GetToken(ctx); err != nil {
+		t.Fatalf("GetToken() error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("sent %d token requests, want 2", got)
+	}
+}
+
+func TestGetTokenSurvivesCancelledCaller(t *testing.T) {
+	t.Parallel()
+	received := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		_ = json.NewEncoder(w).Encode(oidc.AuthResponse{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600})
+	}))
+	t.Cleanup(server.Close)
+	client := newTestClient(server.URL, "")
+
+	// The first caller starts the refresh and is cancelled while it is in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.GetToken(ctx)
+		first <- err
+	}()
+	<-received
+	second := make(chan error, 1)
+	go func() {
+		_, err := client.GetToken(context.Background())
+		second <- err
+	}()
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("GetToken() of the cancelled caller error = %v, want %v", err, context.Canceled)
+	}
+
+	// The other caller still gets the token.
+	close(release)
+	if err := <-second; err != nil {
+		t.Errorf("GetToken() of the waiting caller error = %v", err)
+	}
+}
+
+func TestGetTokenTimeout(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := oidc.NewClient(oidc.Config{
+		TokenEndpoint: server.URL,
+		ClientID:      "key-id",
+		ClientSecret:  "key-secret",
+		Email:         "sa@example.com",
+		Timeout:       20 * time.Millisecond,
+	})
+	start := time.Now()
+	if _, err := client.GetToken(context.Background()); err == nil {
+		t.Error("GetToken() error = nil, want timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("GetToken() took %v, want it to time out after 20ms", elapsed)
+	}
+}
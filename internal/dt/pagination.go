@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the page size used by list requests when Config.PageSize is not set.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page size accepted by the API.
+	MaxPageSize = 1000
+)
+
+// decodePage decodes a page of a list response into its items and the token of the next page.
+type decodePage[T any] func(body []byte) (items []T, nextPageToken string, err error)
+
+// paginate returns an iterator over the items of a list endpoint. It requests
+// the pages one at a time as the iterator is consumed, following the
+// nextPageToken of each page until it is empty. The pages have the page size
+// of the client, unless params sets pageSize. Iteration stops after the first
+// error, which is yielded together with the zero value of T.
+func paginate[T any](ctx context.Context, c *Client, url string, params map[string]string, decode decodePage[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		// Copy the parameters so that the iterator can be consumed more than once.
+		params := maps.Clone(params)
+		if params == nil {
+			params = make(map[string]string)
+		}
+		if _, ok := params["pageSize"]; !ok {
+			params["pageSize"] = strconv.Itoa(c.pageSize)
+		}
+		delete(params, "pageToken")
+
+		for {
+			responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, params)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			items, nextPageToken, err := decode(responseBody)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if nextPageToken == "" {
+				return
+			}
+			params["pageToken"] = nextPageToken
+		}
+	}
+}
+
+// collect returns all the items of a paginated iterator, or the first error.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
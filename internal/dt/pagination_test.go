@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/dtfake"
+)
+
+const testOrganization = "organizations/cvinmt9aq9sc738g6eog"
+
+// newFakeTestClient returns a fake server with a test organization, and a
+// client for it that lists pageSize items per page.
+func newFakeTestClient(t *testing.T, pageSize int) (*dtfake.Server, *dt.Client) {
+	t.Helper()
+	server, client := newTestClient(t, nil, func(cfg *dt.Config) {
+		cfg.PageSize = pageSize
+	})
+	server.AddOrganization(testOrganization, "Test Org")
+	return server, client
+}
+
+func TestListProjectsFollowsPages(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 2)
+	ctx := context.Background()
+
+	var want []string
+	for i := range 5 {
+		name := fmt.Sprintf("projects/project%d", i)
+		server.AddProject(dt.Project{Name: name, Organization: testOrganization})
+		want = append(want, name)
+	}
+
+	var got []string
+	for project, err := range client.ListProjects(ctx, testOrganization) {
+		if err != nil {
+			t.Fatalf("ListProjects() error = %v", err)
+		}
+		got = append(got, project.Name)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("ListProjects() = %v, want %v", got, want)
+	}
+
+	// The last project is on the third page.
+	if _, err := client.GetProject(ctx, "projects/project4", testOrganization); err != nil {
+		t.Errorf("GetProject() error = %v", err)
+	}
+}
+
+func TestListNotificationRulesFollowsPages(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	ctx := context.Background()
+	server.SetPageSize(2)
+	server.AddProject(dt.Project{Name: "projects/project", Organization: testOrganization})
+
+	for i := range 5 {
+		server.AddNotificationRule(dt.NotificationRule{
+			Name:        fmt.Sprintf("projects/project/rules/rule%d", i),
+			DisplayName: fmt.Sprintf("rule %d", i),
+		})
+	}
+
+	count := 0
+	for _, err := range client.ListNotificationRules(ctx, "projects/project") {
+		if err != nil {
+			t.Fatalf("ListNotificationRules() error = %v", err)
+		}
+		count++
+	}
+	if count != 5 {
+		t.Errorf("ListNotificationRules() returned %d rules, want 5", count)
+	}
+
+	rule, err := client.GetNotificationRule(ctx, "projects/project/rules/rule4")
+	if err != nil {
+		t.Fatalf("GetNotificationRule() error = %v", err)
+	}
+	if rule.DisplayName != "rule 4" {
+		t.Errorf("DisplayName = %q, want %q", rule.DisplayName, "rule 4")
+	}
+}
+
+// newPagedTestClient returns a client for an API that lists an endless number
+// of projects, and fails with 500 Internal Server Error on page failPage if it
+// is not zero. The returned counter holds the number of requests the API has
+// received.
+func newPagedTestClient(t *testing.T, pageSize, failPage int) (*dt.Client, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	_, client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := int(requests.Add(1))
+		if got := r.URL.Query().Get("pageSize"); got != strconv.Itoa(pageSize) {
+			t.Errorf("pageSize = %q, want %d", got, pageSize)
+		}
+		if got, want := r.URL.Query().Get("pageToken"), strconv.Itoa(page-1); page > 1 && got != want {
+			t.Errorf("pageToken = %q, want %q", got, want)
+		}
+		if page == failPage {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		response := dt.ListProjectResponse{NextPageToken: strconv.Itoa(page)}
+		for i := range pageSize {
+			response.Projects = append(response.Projects, dt.Project{Name: fmt.Sprintf("projects/p%d-%d", page, i)})
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}), func(cfg *dt.Config) {
+		cfg.PageSize = pageSize
+	})
+	return client, &requests
+}
+
+func TestListFetchesPagesLazily(t *testing.T) {
+	t.Parallel()
+	client, requests := newPagedTestClient(t, 3, 0)
+
+	count := 0
+	for _, err := range client.ListProjects(context.Background(), "") {
+		if err != nil {
+			t.Fatalf("ListProjects() error = %v", err)
+		}
+		count++
+		if count == 4 {
+			break
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("ListProjects() sent %d requests for 4 projects with page size 3, want 2", got)
+	}
+}
+
+func TestListStopsAtFirstError(t *testing.T) {
+	t.Parallel()
+	client, _ := newPagedTestClient(t, 2, 3)
+
+	count := 0
+	var listErr error
+	for _, err := range client.ListProjects(context.Background(), "") {
+		if err != nil {
+			listErr = err
+			continue
+		}
+		count++
+	}
+	if count != 4 {
+		t.Errorf("ListProjects() returned %d projects before the error, want 4", count)
+	}
+	var httpErr *dt.HTTPError
+	if !errors.As(listErr, &httpErr) || httpErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf("ListProjects() error = %v, want HTTP error 500", listErr)
+	}
+}
@@ -6,14 +6,17 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"maps"
 	"net/http"
 	"strings"
-	"sync"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 )
 
 type ListProjectResponse struct {
-	Projects []Project `json:"projects"`
+	Projects      []Project `json:"projects"`
+	NextPageToken string    `json:"nextPageToken"`
 }
 
 type Project struct {
@@ -36,7 +39,11 @@ type EditableProject struct {
 }
 
 func (p Project) ID() (string, error) {
-	return idFromProject(p.Name)
+	projectName, err := names.ParseProjectName(p.Name)
+	if err != nil {
+		return "", err
+	}
+	return projectName.Project, nil
 }
 
 type Location struct {
@@ -45,53 +52,63 @@ type Location struct {
 	TimeLocation string   `json:"timeLocation"`
 }
 
-type projectCache struct {
-	projects map[string]Project
+// GetProject returns a project by resource name. Unless caching is disabled,
+// all the projects in the organization are listed and cached on the first
+// call, so that reading many projects only takes a single list request.
+func (c *Client) GetProject(ctx context.Context, projectName, organizationName string) (Project, error) {
+	// first check if the project is in the cache
+	if project, ok := c.projectCache.get(projectName); ok {
+		return project, nil
+	}
 
-	mu sync.RWMutex
-}
+	// get the project directly if there is no cache to populate
+	if c.projectCache.disabled {
+		return c.getProject(ctx, projectName)
+	}
 
-func (c *projectCache) getProject(name string) (Project, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	if project, ok := c.projects[name]; ok {
-		return project, true
+	// call the API to get all the projects in the org and populate the cache
+	var found bool
+	var project Project
+	for p, err := range c.ListProjects(ctx, organizationName) {
+		if err != nil {
+			return Project{}, fmt.Errorf("failed to list projects: %w", err)
+		}
+		c.projectCache.set(p.Name, p)
+		if p.Name == projectName {
+			project, found = p, true
+		}
+	}
+	if !found {
+		return Project{}, fmt.Errorf("project %w: %s", ErrNotFound, projectName)
 	}
-	return Project{}, false
-}
 
-func (c *projectCache) setProject(project Project) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.projects[project.Name] = project
+	return project, nil
 }
 
-func (c *Client) GetProject(ctx context.Context, projectName, organizationName string) (Project, error) {
-	// first check if the project is in the cache
-	if project, ok := c.projectCache.getProject(projectName); ok {
-		return project, nil
+// getProject gets a single project from the API, bypassing the cache.
+func (c *Client) getProject(ctx context.Context, projectName string) (Project, error) {
+	name, err := names.ParseProjectName(projectName)
+	if err != nil {
+		return Project{}, fmt.Errorf("failed to get project ID: %w", err)
 	}
 
-	// call the API to get all the projects in the org and populate the cache
-	projects, err := c.listProjects(ctx, organizationName)
+	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), name)
+	responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, nil)
 	if err != nil {
-		return Project{}, fmt.Errorf("failed to list projects: %w", err)
+		return Project{}, err
 	}
 
-	// populate the cache with the projects
-	for _, project := range projects.Projects {
-		c.projectCache.setProject(project)
-	}
-	// Now that the cache is populated, we can get the project by name
-	project, ok := c.projectCache.getProject(projectName)
-	if !ok {
-		return Project{}, fmt.Errorf("project not found: %s", projectName)
+	var project Project
+	if err := json.Unmarshal(responseBody, &project); err != nil {
+		return Project{}, err
 	}
-
 	return project, nil
 }
 
-func (c *Client) listProjects(ctx context.Context, organization string) (ListProjectResponse, error) {
+// ListProjects returns an iterator over the projects in the organization, or
+// all the projects the service account has access to if organization is empty.
+func (c *Client) ListProjects(ctx context.Context, organization string) iter.Seq2[Project, error] {
 	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects
 	url := fmt.Sprintf("%s/v2/projects", strings.TrimSuffix(c.URL, "/"))
 
@@ -100,30 +117,24 @@ func (c *Client) listProjects(ctx context.Context, organization string) (ListPro
 		params["organization"] = organization
 	}
 
-	// Send a GET request to the API
-	responseBody, err := c.DoRequest(ctx, http.MethodGet, url, nil, params)
-	if err != nil {
-		return ListProjectResponse{}, err
-	}
-
-	var projects ListProjectResponse
-	err = json.Unmarshal(responseBody, &projects)
-	if err != nil {
-		return ListProjectResponse{}, err
-	}
-
-	return projects, nil
+	return paginate(ctx, c, url, params, func(body []byte) ([]Project, string, error) {
+		var projects ListProjectResponse
+		if err := json.Unmarshal(body, &projects); err != nil {
+			return nil, "", err
+		}
+		return projects.Projects, projects.NextPageToken, nil
+	})
 }
 
 func (c *Client) UpdateProject(ctx context.Context, project EditableProject) (Project, error) {
 	// Get the project ID from the project name
-	projectID, err := idFromProject(project.Name)
+	projectName, err := names.ParseProjectName(project.Name)
 	if err != nil {
 		return Project{}, fmt.Errorf("failed to get project ID: %w", err)
 	}
 
 	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}
-	url := fmt.Sprintf("%s/v2/projects/%s", strings.TrimSuffix(c.URL, "/"), projectID)
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), projectName)
 	body, err := json.Marshal(project)
 	if err != nil {
 		return Project{}, err
@@ -141,7 +152,7 @@ func (c *Client) UpdateProject(ctx context.Context, project EditableProject) (Pr
 	if err != nil {
 		return Project{}, err
 	}
-	c.projectCache.setProject(p)
+	c.projectCache.set(p.Name, p)
 	return p, nil
 }
 
@@ -191,7 +202,7 @@ func (c *Client) SetProjectLabels(ctx context.Context, project Project, targetLa
 	}
 	// Let's assume the correct labels are set.
 	project.Labels = maps.Clone(targetLabels)
-	c.projectCache.setProject(project)
+	c.projectCache.set(project.Name, project)
 	return project, nil
 }
 
@@ -225,6 +236,7 @@ func (c *Client) CreateProject(ctx context.Context, project Project) (Project, e
 	}
 
 	if maps.Equal(project.Labels, p.Labels) {
+		c.projectCache.set(p.Name, p)
 		return p, nil
 	}
 
@@ -237,27 +249,20 @@ func (c *Client) CreateProject(ctx context.Context, project Project) (Project, e
 
 func (c *Client) DeleteProject(ctx context.Context, project string) error {
 	// Get the project ID from the project name
-	projectID, err := idFromProject(project)
+	projectName, err := names.ParseProjectName(project)
 	if err != nil {
 		return fmt.Errorf("failed to get project ID: %w", err)
 	}
 
 	// Create the URL for the API request: https://api.disruptive-technologies.com/v2/projects/{project_id}
-	url := fmt.Sprintf("%s/v2/projects/%s", strings.TrimSuffix(c.URL, "/"), projectID)
+	url := fmt.Sprintf("%s/v2/%s", strings.TrimSuffix(c.URL, "/"), projectName)
 
 	// Send a DELETE request to the API
 	_, err = c.DoRequest(ctx, http.MethodDelete, url, nil, nil)
 	if err != nil {
 		return err
 	}
+	c.projectCache.delete(project)
 
 	return nil
 }
-
-func idFromProject(project string) (string, error) {
-	parts := strings.Split(project, "/")
-	if len(parts) != 2 {
-		return project, fmt.Errorf("invalid project name: %s", project)
-	}
-	return parts[1], nil
-}
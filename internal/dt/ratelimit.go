@@ -0,0 +1,212 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit controls how fast the client sends requests to the API.
+//
+// Terraform runs several resource operations in parallel, and without a limit
+// their requests reach the API in bursts that it rejects with 429 Too Many
+// Requests. Requests wait for a token from a bucket that is refilled at
+// RequestsPerSecond and holds up to Burst tokens, and at most MaxInFlight
+// requests are sent at a time.
+//
+// The limiter adapts to the API. It halves the rate when a request is rate
+// limited, and recovers it gradually as requests succeed. When the API
+// responds with rate limit headers, the requests remaining in the current
+// window are spread out over the rest of the window.
+type RateLimit struct {
+	// Disabled turns the limiter off, so that requests are sent as soon as
+	// they are made.
+	Disabled bool
+	// RequestsPerSecond is the sustained rate of requests.
+	RequestsPerSecond float64
+	// Burst is the number of requests that can be sent at once after a quiet period.
+	Burst int
+	// MaxInFlight is the maximum number of requests that are sent concurrently.
+	MaxInFlight int
+}
+
+// DefaultRateLimit returns the rate limit used for the unset fields of Config.RateLimit.
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		RequestsPerSecond: 20,
+		Burst:             20,
+		MaxInFlight:       10,
+	}
+}
+
+// withDefaults returns the rate limit with unset fields replaced by the defaults.
+func (l RateLimit) withDefaults() RateLimit {
+	defaults := DefaultRateLimit()
+	if l.RequestsPerSecond <= 0 {
+		l.RequestsPerSecond = defaults.RequestsPerSecond
+	}
+	if l.Burst <= 0 {
+		l.Burst = defaults.Burst
+	}
+	if l.MaxInFlight <= 0 {
+		l.MaxInFlight = defaults.MaxInFlight
+	}
+	return l
+}
+
+// unixTimeThreshold separates the rate limit resets that are a number of
+// seconds from the ones that are a Unix time.
+const unixTimeThreshold = 365 * 24 * 60 * 60
+
+// rateLimiter is a token bucket rate limiter with a cap on the number of
+// requests in flight, shared by all requests of a client.
+type rateLimiter struct {
+	disabled bool
+	// limit is the configured rate, which the rate recovers to.
+	limit float64
+	// minRate is the lowest rate the limiter slows down to.
+	minRate  float64
+	burst    float64
+	inFlight chan struct{}
+
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+	// The requests remaining in the rate limit window of the API, and when
+	// the window resets. They are only known if the API sends rate limit headers.
+	windowRemaining int
+	windowReset     time.Time
+}
+
+func newRateLimiter(cfg RateLimit) *rateLimiter {
+	cfg = cfg.withDefaults()
+	return &rateLimiter{
+		disabled: cfg.Disabled,
+		limit:    cfg.RequestsPerSecond,
+		minRate:  cfg.RequestsPerSecond / 10,
+		burst:    float64(cfg.Burst),
+		inFlight: make(chan struct{}, cfg.MaxInFlight),
+		rate:     cfg.RequestsPerSecond,
+		tokens:   float64(cfg.Burst),
+		last:     time.Now(),
+	}
+}
+
+// acquire waits until a request can be sent, and returns a function that
+// releases its place among the requests in flight once it has been sent, and
+// the time it waited for the rate limit.
+func (l *rateLimiter) acquire(ctx context.Context) (func(), time.Duration, error) {
+	if l.disabled {
+		return func() {}, 0, nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	}
+	release := func() { <-l.inFlight }
+
+	wait := l.reserve(time.Now())
+	if err := sleep(ctx, wait); err != nil {
+		release()
+		return nil, 0, err
+	}
+	return release, wait, nil
+}
+
+// reserve takes a token from the bucket, and returns how long to wait until
+// the token is available.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rate := l.rate
+	if now.Before(l.windowReset) {
+		if l.windowRemaining <= 0 {
+			// The requests of the window are used up. Queue for the slots
+			// of the next window instead: the bucket starts again with a
+			// single token when the window resets, so that the waiting
+			// requests are spread out at the rate instead of all sent at
+			// the reset.
+			if l.last.Before(l.windowReset) {
+				l.last = l.windowReset
+				l.tokens = min(l.tokens, 1)
+			}
+		} else {
+			// Spread the remaining requests out over the rest of the window.
+			rate = min(rate, float64(l.windowRemaining)/l.windowReset.Sub(now).Seconds())
+			l.windowRemaining--
+		}
+	}
+
+	// The bucket is ahead of now while requests queue for the next window.
+	if now.After(l.last) {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*rate)
+		l.last = now
+	}
+	l.tokens--
+	wait := l.last.Sub(now)
+	if l.tokens < 0 {
+		wait += time.Duration(-l.tokens / rate * float64(time.Second))
+	}
+	return wait
+}
+
+// observe adapts the limiter to a response from the API.
+func (l *rateLimiter) observe(response *http.Response, now time.Time) {
+	if l.disabled {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	switch {
+	case response.StatusCode == http.StatusTooManyRequests:
+		// Back off, and do not let the requests that are waiting through in a burst.
+		l.rate = max(l.rate/2, l.minRate)
+		l.tokens = min(l.tokens, 0)
+	case response.StatusCode < http.StatusInternalServerError:
+		l.rate = min(l.rate+l.limit/20, l.limit)
+	}
+
+	if remaining, reset, ok := rateLimitHeaders(response.Header, now); ok {
+		l.windowRemaining = remaining
+		l.windowReset = reset
+		l.tokens = min(l.tokens, float64(remaining))
+	}
+}
+
+// rateLimitHeaders returns the number of requests remaining in the current
+// rate limit window, and when the window resets. It reads the RateLimit-
+// Remaining and RateLimit-Reset headers, or their X-RateLimit- variants, where
+// the reset is either a number of seconds or a Unix time. It returns false if
+// the headers are missing or invalid.
+func rateLimitHeaders(header http.Header, now time.Time) (int, time.Time, bool) {
+	remaining, err := strconv.Atoi(firstHeader(header, "RateLimit-Remaining", "X-RateLimit-Remaining"))
+	if err != nil || remaining < 0 {
+		return 0, time.Time{}, false
+	}
+	reset, err := strconv.ParseInt(firstHeader(header, "RateLimit-Reset", "X-RateLimit-Reset"), 10, 64)
+	if err != nil || reset < 0 {
+		return 0, time.Time{}, false
+	}
+	if reset > unixTimeThreshold {
+		return remaining, time.Unix(reset, 0), true
+	}
+	return remaining, now.Add(time.Duration(reset) * time.Second), true
+}
+
+// firstHeader returns the value of the first of the headers that is set.
+func firstHeader(header http.Header, keys ...string) string {
+	for _, key := range keys {
+		if value := header.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
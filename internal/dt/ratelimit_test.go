@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+// newRateLimitTestClient returns a client with the given rate limit for an
+// API that responds with handler. Failed requests are retried right away, so
+// that only the rate limit slows them down.
+func newRateLimitTestClient(t *testing.T, rateLimit dt.RateLimit, handler http.HandlerFunc) *dt.Client {
+	t.Helper()
+	_, client := newTestClient(t, handler, func(cfg *dt.Config) {
+		cfg.Retry = dt.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+		cfg.RateLimit = rateLimit
+	})
+	return client
+}
+
+func respondOK(w http.ResponseWriter, _ *http.Request) {
+	_, _ = w.Write([]byte(`{}`))
+}
+
+// sendRequests sends n requests one after the other, and returns the time it took.
+func sendRequests(t *testing.T, client *dt.Client, n int) time.Duration {
+	t.Helper()
+	start := time.Now()
+	for range n {
+		if _, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/v2/projects", nil, nil); err != nil {
+			t.Fatalf("DoRequest() error = %v", err)
+		}
+	}
+	return time.Since(start)
+}
+
+func TestRateLimitSpreadsRequests(t *testing.T) {
+	t.Parallel()
+	client := newRateLimitTestClient(t, dt.RateLimit{RequestsPerSecond: 50, Burst: 2}, respondOK)
+
+	// The first two requests use up the burst, and the other four wait 20ms each.
+	if elapsed := sendRequests(t, client, 6); elapsed < 80*time.Millisecond {
+		t.Errorf("sending 6 requests took %v, want at least 80ms", elapsed)
+	}
+	if wait := client.UsageReport().RateLimitWaitSeconds; wait <= 0 {
+		t.Errorf("UsageReport().RateLimitWaitSeconds = %v, want more than 0", wait)
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	t.Parallel()
+	client := newRateLimitTestClient(t, dt.RateLimit{Disabled: true, RequestsPerSecond: 1, Burst: 1}, respondOK)
+
+	if elapsed := sendRequests(t, client, 3); elapsed > 500*time.Millisecond {
+		t.Errorf("sending 3 requests took %v, want them to not be limited", elapsed)
+	}
+}
+
+func TestRateLimitCapsRequestsInFlight(t *testing.T) {
+	t.Parallel()
+	var inFlight, maxInFlight atomic.Int32
+	client := newRateLimitTestClient(t, dt.RateLimit{RequestsPerSecond: 1000, Burst: 100, MaxInFlight: 2}, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		respondOK(w, r)
+	})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/v2/projects", nil, nil); err != nil {
+				t.Errorf("DoRequest() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got != 2 {
+		t.Errorf("requests in flight = %d, want 2", got)
+	}
+}
+
+func TestRateLimitFollowsRateLimitHeaders(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	client := newRateLimitTestClient(t, dt.RateLimit{RequestsPerSecond: 1000, Burst: 100}, func(w http.ResponseWriter, r *http.Request) {
+		// The first response uses up the window, which resets a second later.
+		if requests.Add(1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1")
+		}
+		respondOK(w, r)
+	})
+
+	if elapsed := sendRequests(t, client, 2); elapsed < 900*time.Millisecond {
+		t.Errorf("sending 2 requests took %v, want the second to wait for the window to reset", elapsed)
+	}
+}
+
+func TestRateLimitSpreadsRequestsWaitingForTheWindow(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var times []time.Time
+	client := newRateLimitTestClient(t, dt.RateLimit{RequestsPerSecond: 20, Burst: 20}, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		// The first response uses up the window, which resets a second later.
+		if times = append(times, time.Now()); len(times) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1")
+		}
+		respondOK(w, r)
+	})
+	sendRequests(t, client, 1)
+
+	// The requests waiting for the window are sent about 50ms apart after it
+	// resets, instead of all at once.
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sendRequests(t, client, 1)
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := times[1].Sub(times[0]); got < 900*time.Millisecond {
+		t.Errorf("first request after the window was sent after %v, want it to wait for the reset", got)
+	}
+	if got := times[5].Sub(times[1]); got < 150*time.Millisecond {
+		t.Errorf("requests after the window were sent within %v, want them spread over about 200ms", got)
+	}
+}
+
+func TestRateLimitSlowsDownWhenRateLimited(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	client := newRateLimitTestClient(t, dt.RateLimit{RequestsPerSecond: 20, Burst: 20}, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		respondOK(w, r)
+	})
+
+	// The 429 drops the burst and halves the rate, so the retry and the
+	// following request are sent about 100ms apart, instead of at once.
+	if elapsed := sendRequests(t, client, 2); elapsed < 150*time.Millisecond {
+		t.Errorf("sending 2 requests took %v, want at least 150ms", elapsed)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package redact masks secrets in the debug logs of the dt and oidc clients.
+//
+// Request and response bodies are logged at the debug level, and may contain
+// access tokens, signed JWT assertions, and secrets such as the signature
+// secret and headers of webhooks. Context configures the tflog logger of a
+// context to mask them, so that logs can be shared safely.
+package redact
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Mask is what tflog replaces masked values with.
+const Mask = "***"
+
+// sensitiveFieldKeys are the log fields whose values are always masked.
+var sensitiveFieldKeys = []string{
+	"jwt",
+	"assertion",
+	"access_token",
+	"authorization",
+	"Authorization",
+}
+
+// sensitivePatterns match secrets in logged messages and field values, such
+// as the JSON request and response bodies.
+var sensitivePatterns = []*regexp.Regexp{
+	// Secret string values in JSON objects, like the signatureSecret of
+	// webhooks and the clientSecret of Corrigo actions.
+	regexp.MustCompile(`"(?:signatureSecret|clientSecret|secret|password|access_token|accessToken|refresh_token|refreshToken|id_token|assertion)"\s*:\s*"(?:[^"\\]|\\.)*"`),
+	// Webhook headers, which typically carry credentials for the receiver.
+	regexp.MustCompile(`"headers"\s*:\s*\{[^{}]*\}`),
+	// The JWT assertion of form encoded token requests.
+	regexp.MustCompile(`assertion=[^&\s"]+`),
+	// Bearer tokens, e.g. in Authorization headers.
+	regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	// JWTs anywhere else.
+	regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+}
+
+// Context returns a context whose logger masks the known sensitive fields and
+// patterns, and every occurrence of the given secrets, such as the service
+// account key secret. Empty secrets are ignored.
+//
+// If unsafe is true, the context is returned unchanged so that secrets are
+// logged in full. This is only meant for debugging locally.
+func Context(ctx context.Context, unsafe bool, secrets ...string) context.Context {
+	if unsafe {
+		return ctx
+	}
+
+	ctx = tflog.MaskFieldValuesWithFieldKeys(ctx, sensitiveFieldKeys...)
+	ctx = tflog.MaskLogRegexes(ctx, sensitivePatterns...)
+
+	var nonEmpty []string
+	for _, secret := range secrets {
+		if secret != "" {
+			nonEmpty = append(nonEmpty, secret)
+		}
+	}
+	if len(nonEmpty) > 0 {
+		ctx = tflog.MaskLogStrings(ctx, nonEmpty...)
+	}
+	return ctx
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how DoRequest retries failed requests.
+//
+// Requests are retried when the API responds with one of RetryableStatusCodes
+// or when the request fails before a response is received, such as on a
+// connection reset. Requests that are not idempotent, like the POST requests
+// that create resources, are only retried on 429 Too Many Requests unless
+// RetryNonIdempotent is set, since the server might already have processed
+// them.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry. It doubles for every
+	// following retry, up to MaxBackoff.
+	MinBackoff time.Duration
+	// MaxBackoff is the longest wait between two attempts.
+	MaxBackoff time.Duration
+	// RetryableStatusCodes are the HTTP status codes that are retried.
+	RetryableStatusCodes []int
+	// RetryNonIdempotent enables retries of requests that are not idempotent.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the retry policy used when Config.Retry is not set.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// withDefaults returns the policy with unset fields replaced by the defaults.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	defaults := DefaultRetryPolicy()
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.MinBackoff <= 0 {
+		p.MinBackoff = defaults.MinBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	p.MaxBackoff = max(p.MaxBackoff, p.MinBackoff)
+	if p.RetryableStatusCodes == nil {
+		p.RetryableStatusCodes = defaults.RetryableStatusCodes
+	}
+	return p
+}
+
+// backoff returns the wait before the given retry, starting at 1. The wait is
+// chosen at random between half and all of the exponential backoff, so that
+// parallel requests do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := p.MaxBackoff
+	// Stop doubling before the shift can overflow.
+	if shift := retry - 1; shift < 32 {
+		backoff = min(p.MinBackoff<<shift, p.MaxBackoff)
+	}
+	half := backoff / 2
+	return half + rand.N(backoff-half+1)
+}
+
+// retryStatus reports whether a response with the given status code should be retried.
+func (p RetryPolicy) retryStatus(method, url string, statusCode int) bool {
+	if !slices.Contains(p.RetryableStatusCodes, statusCode) {
+		return false
+	}
+	// A rate limited request was rejected before it was processed, so it is
+	// always safe to send it again.
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return p.RetryNonIdempotent || isIdempotent(method, url)
+}
+
+// retryError reports whether a request that failed without a response should be retried.
+func (p RetryPolicy) retryError(ctx context.Context, method, url string, err error) bool {
+	// Do not retry when the request was cancelled, e.g. by Ctrl-C.
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	// Nor when the server certificate is not trusted, which retrying does not change.
+	var certificateErr *tls.CertificateVerificationError
+	if errors.As(err, &certificateErr) {
+		return false
+	}
+	return p.RetryNonIdempotent || isIdempotent(method, url)
+}
+
+// isIdempotent reports whether sending the request more than once has the same
+// effect as sending it once. The API applies PATCH requests with an update
+// mask, and the batch update and delete methods set labels and remove members,
+// so these are idempotent as well.
+func isIdempotent(method, url string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete, http.MethodPatch:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(url, ":batchUpdate") || strings.HasSuffix(url, ":batchDelete")
+	}
+	return false
+}
+
+// sleep waits for the duration or until the context is done, whichever happens first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
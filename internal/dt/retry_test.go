@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+// newRetryTestClient returns a client with the given retry policy for an API
+// that responds with the given status codes in order, and 200 OK once they are
+// used up. The returned counter holds the number of requests the API has
+// received.
+func newRetryTestClient(t *testing.T, policy dt.RetryPolicy, statusCodes ...int) (*dt.Client, *atomic.Int32) {
+	t.Helper()
+	handler, requests := statusCodesHandler(statusCodes...)
+	_, client := newTestClient(t, handler, func(cfg *dt.Config) {
+		cfg.Retry = policy
+	})
+	return client, requests
+}
+
+func TestDoRequestRetries(t *testing.T) {
+	t.Parallel()
+	fastPolicy := dt.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	tests := []struct {
+		name         string
+		policy       dt.RetryPolicy
+		method       string
+		path         string
+		statusCodes  []int
+		wantRequests int32
+		wantStatus   int
+	}{
+		{
+			name:         "retries gateway errors",
+			policy:       fastPolicy,
+			method:       http.MethodGet,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			policy:       fastPolicy,
+			method:       http.MethodGet,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout},
+			wantRequests: 3,
+			wantStatus:   http.StatusGatewayTimeout,
+		},
+		{
+			name:         "does not retry client errors",
+			policy:       fastPolicy,
+			method:       http.MethodGet,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusBadRequest},
+			wantRequests: 1,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:         "does not retry creates on gateway errors",
+			policy:       fastPolicy,
+			method:       http.MethodPost,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusServiceUnavailable},
+			wantRequests: 1,
+			wantStatus:   http.StatusServiceUnavailable,
+		},
+		{
+			name:         "retries rate limited creates",
+			policy:       fastPolicy,
+			method:       http.MethodPost,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusTooManyRequests},
+			wantRequests: 2,
+		},
+		{
+			name:         "retries batch updates",
+			policy:       fastPolicy,
+			method:       http.MethodPost,
+			path:         "/v2/projects:batchUpdate",
+			statusCodes:  []int{http.StatusServiceUnavailable},
+			wantRequests: 2,
+		},
+		{
+			name: "retries creates when enabled",
+			policy: dt.RetryPolicy{
+				MaxAttempts:        3,
+				MinBackoff:         time.Millisecond,
+				MaxBackoff:         time.Millisecond,
+				RetryNonIdempotent: true,
+			},
+			method:       http.MethodPost,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusServiceUnavailable},
+			wantRequests: 2,
+		},
+		{
+			name: "retries configured status codes",
+			policy: dt.RetryPolicy{
+				MaxAttempts:          3,
+				MinBackoff:           time.Millisecond,
+				MaxBackoff:           time.Millisecond,
+				RetryableStatusCodes: []int{http.StatusInternalServerError},
+			},
+			method:       http.MethodGet,
+			path:         "/v2/projects",
+			statusCodes:  []int{http.StatusInternalServerError, http.StatusBadGateway},
+			wantRequests: 2,
+			wantStatus:   http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, requests := newRetryTestClient(t, tt.policy, tt.statusCodes...)
+
+			_, err := client.DoRequest(context.Background(), tt.method, client.URL+tt.path, []byte(`{}`), nil)
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("DoRequest() sent %d requests, want %d", got, tt.wantRequests)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Errorf("DoRequest() error = %v, want nil", err)
+				}
+				return
+			}
+			var httpErr *dt.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.wantStatus {
+				t.Errorf("DoRequest() error = %v, want HTTP error %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestDoRequestRetryIsCancelledWithContext(t *testing.T) {
+	t.Parallel()
+	client, requests := newRetryTestClient(t, dt.RetryPolicy{
+		MaxAttempts: 5,
+		MinBackoff:  time.Hour,
+		MaxBackoff:  time.Hour,
+	}, http.StatusServiceUnavailable)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.DoRequest(ctx, http.MethodGet, client.URL+"/v2/projects", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DoRequest() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("DoRequest() returned after %s, want it to stop waiting when the context is done", elapsed)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("DoRequest() sent %d requests, want 1", got)
+	}
+}
+
+func TestDoRequestRetriesConnectionErrors(t *testing.T) {
+	t.Parallel()
+	// Drop the connection of the first request without responding.
+	var requests atomic.Int32
+	_, client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}), func(cfg *dt.Config) {
+		cfg.Retry = dt.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	})
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/v2/projects", nil, nil); err != nil {
+		t.Errorf("DoRequest() error = %v, want nil", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("DoRequest() sent %d requests, want 2", got)
+	}
+}
+
+func TestDoRequestRequestsNewTokenOnUnauthorized(t *testing.T) {
+	t.Parallel()
+	server, client := newFakeTestClient(t, 0)
+	server.AddProject(dt.Project{Name: "projects/project", Organization: testOrganization})
+	ctx := context.Background()
+
+	if _, err := client.DoRequest(ctx, http.MethodGet, server.URL+"/v2/projects/project", nil, nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	// The cached token is still valid as far as the client knows.
+	server.RevokeTokens()
+	if _, err := client.DoRequest(ctx, http.MethodGet, server.URL+"/v2/projects/project", nil, nil); err != nil {
+		t.Errorf("DoRequest() after revoking tokens error = %v", err)
+	}
+}
+
+func TestDoRequestRequestsNewTokenOnlyOnce(t *testing.T) {
+	t.Parallel()
+	client, requests := newRetryTestClient(t, dt.RetryPolicy{}, http.StatusUnauthorized, http.StatusUnauthorized)
+
+	_, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/", nil, nil)
+	if !errors.Is(err, dt.ErrUnauthenticated) {
+		t.Errorf("DoRequest() error = %v, want %v", err, dt.ErrUnauthenticated)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("DoRequest() sent %d requests, want 2", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"context"
+	"errors"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the client.
+const tracerName = "github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+
+// Attributes of the spans of requests to the API, in addition to the
+// OpenTelemetry semantic conventions for HTTP clients.
+const (
+	// attributeResourceName is the name of the resource a request is for, such as projects/{project}.
+	attributeResourceName = attribute.Key("dt.resource.name")
+	// attributeAttempts is the number of times a request was sent.
+	attributeAttempts = attribute.Key("dt.request.attempts")
+)
+
+// apiVersion matches the API version segment of request paths, such as v2 or v2alpha.
+var apiVersion = regexp.MustCompile(`^v\d+((alpha|beta)\d*)?$`)
+
+// resourceName returns the resource name in the path of a request URL,
+// which is the part of the path after the API version.
+func resourceName(url string) string {
+	u, err := neturl.Parse(url)
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, segment := range segments {
+		if apiVersion.MatchString(segment) {
+			return strings.Join(segments[i+1:], "/")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// startSpan starts the span of a request to the API.
+func (c *Client) startSpan(ctx context.Context, method, url string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.request.method", method),
+		attribute.String("url.full", url),
+		attributeResourceName.String(resourceName(url)),
+	))
+}
+
+// endSpan ends the span of a request to the API, recording the error if the request failed.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			span.SetAttributes(attribute.String("error.type", strconv.Itoa(httpErr.StatusCode)))
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,90 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTracingTestClient returns a client for an API that responds with the
+// given status codes in order, and 200 OK once they are used up, and a
+// recorder of the spans of the client.
+func newTracingTestClient(t *testing.T, statusCodes ...int) (*dt.Client, *tracetest.SpanRecorder) {
+	t.Helper()
+	handler, _ := statusCodesHandler(statusCodes...)
+	recorder := tracetest.NewSpanRecorder()
+	_, client := newTestClient(t, handler, func(cfg *dt.Config) {
+		cfg.Retry = dt.RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+		cfg.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	})
+	return client, recorder
+}
+
+// spanAttributes returns the attributes of a span by key.
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+func TestDoRequestSpan(t *testing.T) {
+	t.Parallel()
+	client, recorder := newTracingTestClient(t, http.StatusServiceUnavailable)
+
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/v2alpha/projects/project/rules/rule", nil, nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("DoRequest() recorded %d spans, want 1", len(spans))
+	}
+	attributes := spanAttributes(spans[0])
+	for key, want := range map[attribute.Key]attribute.Value{
+		"http.request.method":       attribute.StringValue(http.MethodGet),
+		"dt.resource.name":          attribute.StringValue("projects/project/rules/rule"),
+		"dt.request.attempts":       attribute.IntValue(2),
+		"http.response.status_code": attribute.IntValue(http.StatusOK),
+	} {
+		if got := attributes[key]; got != want {
+			t.Errorf("attribute %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+	if events := spans[0].Events(); len(events) != 1 || events[0].Name != "retry" {
+		t.Errorf("span events = %v, want one retry event", events)
+	}
+	if status := spans[0].Status(); status.Code != codes.Unset {
+		t.Errorf("span status = %v, want %v", status.Code, codes.Unset)
+	}
+}
+
+func TestDoRequestSpanRecordsErrors(t *testing.T) {
+	t.Parallel()
+	client, recorder := newTracingTestClient(t, http.StatusNotFound)
+
+	if _, err := client.DoRequest(context.Background(), http.MethodDelete, client.URL+"/v2/projects/project", nil, nil); err == nil {
+		t.Fatal("DoRequest() error = nil, want an error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("DoRequest() recorded %d spans, want 1", len(spans))
+	}
+	if status := spans[0].Status(); status.Code != codes.Error {
+		t.Errorf("span status = %v, want %v", status.Code, codes.Error)
+	}
+	if got := spanAttributes(spans[0])["error.type"]; got.AsString() != "404" {
+		t.Errorf("attribute error.type = %q, want %q", got.AsString(), "404")
+	}
+}
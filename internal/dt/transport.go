@@ -0,0 +1,72 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"time"
+)
+
+// TransportConfig configures the HTTP client shared by the API client and the
+// OIDC client, for networks that require a proxy or TLS inspection.
+type TransportConfig struct {
+	// ProxyURL is the URL of the proxy to send requests through. If it is
+	// empty, the proxy is read from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	// environment variables.
+	ProxyURL string
+	// CACertificates are PEM encoded certificates that are trusted in addition
+	// to the system certificate pool, such as the certificate of a TLS
+	// inspecting proxy.
+	CACertificates []byte
+	// ClientCertificate and ClientKey are a PEM encoded certificate and key
+	// that the client authenticates with, for servers that require mutual TLS.
+	ClientCertificate []byte
+	ClientKey         []byte
+	// RequestTimeout is the timeout of each HTTP request, including reading
+	// the response body. Zero means no timeout.
+	RequestTimeout time.Duration
+}
+
+// NewHTTPClient returns an HTTP client configured by cfg.
+func NewHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := neturl.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("dt: invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if len(cfg.CACertificates) > 0 || len(cfg.ClientCertificate) > 0 || len(cfg.ClientKey) > 0 {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if len(cfg.CACertificates) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(cfg.CACertificates) {
+			return nil, errors.New("dt: failed to parse CA certificates: no PEM encoded certificates found")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	if len(cfg.ClientCertificate) > 0 || len(cfg.ClientKey) > 0 {
+		certificate, err := tls.X509KeyPair(cfg.ClientCertificate, cfg.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("dt: failed to parse client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.RequestTimeout,
+	}, nil
+}
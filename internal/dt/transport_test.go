@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+)
+
+// newTransportTestClient returns a client for the API at url, with tokens
+// issued by a fake identity server, that sends requests with httpClient.
+func newTransportTestClient(t *testing.T, url string, httpClient *http.Client, timeout time.Duration) *dt.Client {
+	t.Helper()
+	_, client := newTestClient(t, nil, func(cfg *dt.Config) {
+		cfg.URL = url
+		cfg.HTTPClient = httpClient
+		cfg.Timeout = timeout
+		cfg.Retry = dt.RetryPolicy{MaxAttempts: 100, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	})
+	return client
+}
+
+// serverCertificatePEM returns the PEM encoded certificate of a TLS test server.
+func serverCertificatePEM(server *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+}
+
+// newClientCertificate returns a PEM encoded self-signed client certificate and its key.
+func newClientCertificate(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestHTTPClientTrustsCACertificates(t *testing.T) {
+	t.Parallel()
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	// Silence the log of the failed TLS handshake.
+	api.Config.ErrorLog = log.New(io.Discard, "", 0)
+	api.StartTLS()
+	t.Cleanup(api.Close)
+
+	untrusted, err := dt.NewHTTPClient(dt.TransportConfig{})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	client := newTransportTestClient(t, api.URL, untrusted, 0)
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, api.URL, nil, nil); err == nil {
+		t.Error("DoRequest() to a server with an untrusted certificate error = nil, want an error")
+	}
+
+	trusted, err := dt.NewHTTPClient(dt.TransportConfig{CACertificates: serverCertificatePEM(api)})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	client = newTransportTestClient(t, api.URL, trusted, 0)
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, api.URL, nil, nil); err != nil {
+		t.Errorf("DoRequest() error = %v", err)
+	}
+}
+
+func TestHTTPClientSendsClientCertificate(t *testing.T) {
+	t.Parallel()
+	var commonName atomic.Value
+	api := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		commonName.Store(r.TLS.PeerCertificates[0].Subject.CommonName)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	api.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	api.StartTLS()
+	t.Cleanup(api.Close)
+
+	certificate, key := newClientCertificate(t)
+	httpClient, err := dt.NewHTTPClient(dt.TransportConfig{
+		CACertificates:    serverCertificatePEM(api),
+		ClientCertificate: certificate,
+		ClientKey:         key,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	client := newTransportTestClient(t, api.URL, httpClient, 0)
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, api.URL, nil, nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if got := commonName.Load(); got != "terraform" {
+		t.Errorf("client certificate common name = %v, want %q", got, "terraform")
+	}
+}
+
+func TestHTTPClientSendsRequestsThroughProxy(t *testing.T) {
+	t.Parallel()
+	// The proxy answers the requests itself, both to the API and to the token endpoint.
+	var hosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.URL.Host)
+		if r.URL.Path == "/oauth2/token" {
+			_ = json.NewEncoder(w).Encode(oidc.AuthResponse{AccessToken: "token", TokenType: "Bearer", ExpiresIn: 3600})
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	httpClient, err := dt.NewHTTPClient(dt.TransportConfig{ProxyURL: proxy.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPClient() error = %v", err)
+	}
+	_, client := newTestClient(t, nil, func(cfg *dt.Config) {
+		cfg.URL = "http://api.example.com"
+		cfg.HTTPClient = httpClient
+		cfg.Oidc.TokenEndpoint = "http://identity.example.com/oauth2/token"
+	})
+	if _, err := client.DoRequest(context.Background(), http.MethodGet, client.URL+"/v2/projects", nil, nil); err != nil {
+		t.Fatalf("DoRequest() error = %v", err)
+	}
+	if want := []string{"identity.example.com", "api.example.com"}; len(hosts) != 2 || hosts[0] != want[0] || hosts[1] != want[1] {
+		t.Errorf("proxy received requests for %v, want %v", hosts, want)
+	}
+}
+
+func TestHTTPClientRejectsInvalidCertificates(t *testing.T) {
+	t.Parallel()
+	certificate, _ := newClientCertificate(t)
+	tests := []struct {
+		name string
+		cfg  dt.TransportConfig
+	}{
+		{name: "CA bundle without certificates", cfg: dt.TransportConfig{CACertificates: []byte("not a certificate")}},
+		{name: "client certificate without key", cfg: dt.TransportConfig{ClientCertificate: certificate}},
+		{name: "invalid proxy URL", cfg: dt.TransportConfig{ProxyURL: "http://proxy.example.com:port"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := dt.NewHTTPClient(tt.cfg); err == nil {
+				t.Error("NewHTTPClient() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDoRequestTimeout(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		cfg     dt.TransportConfig
+		timeout time.Duration
+	}{
+		// The API hangs, so the requests time out.
+		{name: "request timeout", cfg: dt.TransportConfig{RequestTimeout: 20 * time.Millisecond}},
+		{name: "timeout", timeout: 20 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			}))
+			t.Cleanup(api.Close)
+			httpClient, err := dt.NewHTTPClient(tt.cfg)
+			if err != nil {
+				t.Fatalf("NewHTTPClient() error = %v", err)
+			}
+			client := newTransportTestClient(t, api.URL, httpClient, tt.timeout)
+
+			start := time.Now()
+			_, err = client.DoRequest(context.Background(), http.MethodGet, api.URL, nil, nil)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("DoRequest() error = %v, want %v", err, context.DeadlineExceeded)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("DoRequest() took %v, want it to time out after %v", elapsed, tt.timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt
+
+import (
+	"cmp"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UsageReport summarizes the requests a client has sent to the API. Use it to
+// size the rate limits of service accounts, and to notice when a configuration
+// starts to send more requests than before.
+type UsageReport struct {
+	// Requests are the requests sent to each endpoint, sorted by endpoint and method.
+	Requests []EndpointUsage `json:"requests"`
+	// TotalRequests is the number of requests sent to the API, including retries.
+	TotalRequests int `json:"total_requests"`
+	// RateLimited is the number of requests that failed with 429 Too Many Requests.
+	RateLimited int `json:"rate_limited"`
+	// RetryAfterWaitSeconds is the time spent waiting for the Retry-After time of rate limited requests.
+	RetryAfterWaitSeconds float64 `json:"retry_after_wait_seconds"`
+	// RateLimitWaitSeconds is the time requests spent waiting for the rate limiter of the client.
+	RateLimitWaitSeconds float64 `json:"rate_limit_wait_seconds"`
+	// TokenFetches is the number of access tokens requested from the token endpoint.
+	TokenFetches int `json:"token_fetches"`
+	// Caches are the hits and misses of the caches, by cache name.
+	Caches map[string]CacheUsage `json:"caches"`
+}
+
+// EndpointUsage is the number of requests sent to an endpoint with a method.
+type EndpointUsage struct {
+	Method string `json:"method"`
+	// Endpoint is the path of the requests, with the resource IDs replaced by *,
+	// such as /v2/projects/*/devices.
+	Endpoint string `json:"endpoint"`
+	Requests int    `json:"requests"`
+	// Errors is the number of requests that failed.
+	Errors int `json:"errors"`
+}
+
+// CacheUsage is the number of reads that were served from a cache, and the
+// number that were sent to the API.
+type CacheUsage struct {
+	Disabled bool    `json:"disabled"`
+	Hits     int     `json:"hits"`
+	Misses   int     `json:"misses"`
+	HitRate  float64 `json:"hit_rate"`
+}
+
+// usage counts the requests a client sends to the API.
+type usage struct {
+	mu             sync.Mutex
+	endpoints      map[endpoint]*EndpointUsage
+	rateLimited    int
+	retryAfterWait time.Duration
+	rateLimitWait  time.Duration
+}
+
+type endpoint struct {
+	method string
+	path   string
+}
+
+func newUsage() *usage {
+	return &usage{endpoints: make(map[endpoint]*EndpointUsage)}
+}
+
+// request counts a request that was sent to the API, and failed with the
+// status code if it is not 200 OK. The status code is zero if the request
+// failed without a response.
+func (u *usage) request(method, url string, statusCode int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	key := endpoint{method: method, path: endpointPath(url)}
+	e, ok := u.endpoints[key]
+	if !ok {
+		e = &EndpointUsage{Method: key.method, Endpoint: key.path}
+		u.endpoints[key] = e
+	}
+	e.Requests++
+	if statusCode != http.StatusOK {
+		e.Errors++
+	}
+	if statusCode == http.StatusTooManyRequests {
+		u.rateLimited++
+	}
+}
+
+// wait counts time spent waiting for the Retry-After time.
+func (u *usage) wait(d time.Duration) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.retryAfterWait += d
+}
+
+// limit counts time spent waiting for the rate limiter.
+func (u *usage) limit(d time.Duration) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.rateLimitWait += d
+}
+
+// endpointPath returns the path of a request URL with the resource IDs
+// replaced by *. In resource names such as projects/{project}/rules/{rule},
+// every second segment after the API version is an ID. Custom methods such as
+// devices:batchUpdate are kept.
+func endpointPath(url string) string {
+	u, err := neturl.Parse(url)
+	if err != nil {
+		return url
+	}
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	start := slices.IndexFunc(segments, apiVersion.MatchString) + 1
+	for i := start + 1; i < len(segments); i += 2 {
+		id, method, found := strings.Cut(segments[i], ":")
+		if id == "" {
+			continue
+		}
+		segments[i] = "*"
+		if found {
+			segments[i] += ":" + method
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// UsageReport returns a report of the requests the client has sent to the API so far.
+func (c *Client) UsageReport() UsageReport {
+	c.usage.mu.Lock()
+	report := UsageReport{
+		RateLimited:           c.usage.rateLimited,
+		RetryAfterWaitSeconds: c.usage.retryAfterWait.Seconds(),
+		RateLimitWaitSeconds:  c.usage.rateLimitWait.Seconds(),
+	}
+	for _, e := range c.usage.endpoints {
+		report.Requests = append(report.Requests, *e)
+		report.TotalRequests += e.Requests
+	}
+	c.usage.mu.Unlock()
+
+	slices.SortFunc(report.Requests, func(a, b EndpointUsage) int {
+		return cmp.Or(strings.Compare(a.Endpoint, b.Endpoint), strings.Compare(a.Method, b.Method))
+	})
+	report.TokenFetches = c.oidc.TokenFetches()
+	report.Caches = map[string]CacheUsage{
+		"projects":           c.projectCache.usage(),
+		"notification_rules": c.rulesCache.usage(),
+	}
+	return report
+}
+
+// Add returns the sum of two reports, such as the reports of the provider
+// processes that Terraform starts for a run.
+func (r UsageReport) Add(other UsageReport) UsageReport {
+	sum := UsageReport{
+		TotalRequests:         r.TotalRequests + other.TotalRequests,
+		RateLimited:           r.RateLimited + other.RateLimited,
+		RetryAfterWaitSeconds: r.RetryAfterWaitSeconds + other.RetryAfterWaitSeconds,
+		RateLimitWaitSeconds:  r.RateLimitWaitSeconds + other.RateLimitWaitSeconds,
+		TokenFetches:          r.TokenFetches + other.TokenFetches,
+		Caches:                make(map[string]CacheUsage),
+	}
+
+	endpoints := make(map[endpoint]EndpointUsage)
+	for _, e := range slices.Concat(r.Requests, other.Requests) {
+		key := endpoint{method: e.Method, path: e.Endpoint}
+		total := endpoints[key]
+		total.Method, total.Endpoint = e.Method, e.Endpoint
+		total.Requests += e.Requests
+		total.Errors += e.Errors
+		endpoints[key] = total
+	}
+	for _, e := range endpoints {
+		sum.Requests = append(sum.Requests, e)
+	}
+	slices.SortFunc(sum.Requests, func(a, b EndpointUsage) int {
+		return cmp.Or(strings.Compare(a.Endpoint, b.Endpoint), strings.Compare(a.Method, b.Method))
+	})
+
+	for _, caches := range []map[string]CacheUsage{r.Caches, other.Caches} {
+		for name, cache := range caches {
+			total, ok := sum.Caches[name]
+			// A cache is only disabled if it is disabled in every report.
+			total.Disabled = cache.Disabled && (!ok || total.Disabled)
+			total.Hits += cache.Hits
+			total.Misses += cache.Misses
+			if reads := total.Hits + total.Misses; reads > 0 {
+				total.HitRate = float64(total.Hits) / float64(reads)
+			}
+			sum.Caches[name] = total
+		}
+	}
+	return sum
+}
@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+
+package dt_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func TestUsageReportCountsRequestsByEndpoint(t *testing.T) {
+	t.Parallel()
+	policy := dt.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	client, _ := newRetryTestClient(t, policy, http.StatusTooManyRequests)
+	ctx := context.Background()
+
+	for _, request := range []struct{ method, path string }{
+		{http.MethodGet, "/v2/projects/a"},
+		{http.MethodGet, "/v2/projects/b"},
+		{http.MethodGet, "/v2/projects"},
+		{http.MethodPost, "/v2/projects/a/devices:batchUpdate"},
+		{http.MethodGet, "/v2alpha/projects/a/rules/r"},
+	} {
+		if _, err := client.DoRequest(ctx, request.method, client.URL+request.path, nil, nil); err != nil {
+			t.Fatalf("DoRequest(%s %s) error = %v", request.method, request.path, err)
+		}
+	}
+
+	report := client.UsageReport()
+	want := []dt.EndpointUsage{
+		{Method: http.MethodGet, Endpoint: "/v2/projects", Requests: 1},
+		{Method: http.MethodGet, Endpoint: "/v2/projects/*", Requests: 3, Errors: 1},
+		{Method: http.MethodPost, Endpoint: "/v2/projects/*/devices:batchUpdate", Requests: 1},
+		{Method: http.MethodGet, Endpoint: "/v2alpha/projects/*/rules/*", Requests: 1},
+	}
+	if !reflect.DeepEqual(report.Requests, want) {
+		t.Errorf("UsageReport().Requests = %+v, want %+v", report.Requests, want)
+	}
+	if report.TotalRequests != 6 {
+		t.Errorf("UsageReport().TotalRequests = %d, want 6", report.TotalRequests)
+	}
+	if report.RateLimited != 1 {
+		t.Errorf("UsageReport().RateLimited = %d, want 1", report.RateLimited)
+	}
+	if report.TokenFetches != 1 {
+		t.Errorf("UsageReport().TokenFetches = %d, want 1", report.TokenFetches)
+	}
+}
+
+func TestUsageReportCountsCacheHits(t *testing.T) {
+	t.Parallel()
+	_, client := newCacheTestClient(t, dt.CacheConfig{})
+	ctx := context.Background()
+
+	for range 3 {
+		if _, err := client.GetProject(ctx, "projects/project", testOrganization); err != nil {
+			t.Fatalf("GetProject() error = %v", err)
+		}
+	}
+
+	got := client.UsageReport().Caches["projects"]
+	want := dt.CacheUsage{Hits: 2, Misses: 1, HitRate: 2.0 / 3}
+	if got != want {
+		t.Errorf("UsageReport().Caches[projects] = %+v, want %+v", got, want)
+	}
+}
+
+func TestUsageReportAdd(t *testing.T) {
+	t.Parallel()
+	a := dt.UsageReport{
+		Requests: []dt.EndpointUsage{
+			{Method: "GET", Endpoint: "/v2/projects", Requests: 2},
+			{Method: "GET", Endpoint: "/v2/projects/*/devices", Requests: 3, Errors: 1},
+		},
+		TotalRequests:         5,
+		RateLimited:           1,
+		RetryAfterWaitSeconds: 1.5,
+		TokenFetches:          1,
+		Caches:                map[string]dt.CacheUsage{"projects": {Hits: 1, Misses: 1, HitRate: 0.5}},
+	}
+	b := dt.UsageReport{
+		Requests: []dt.EndpointUsage{
+			{Method: "POST", Endpoint: "/v2/projects", Requests: 1},
+			{Method: "GET", Endpoint: "/v2/projects", Requests: 1},
+		},
+		TotalRequests:        2,
+		RateLimitWaitSeconds: 0.5,
+		Caches:               map[string]dt.CacheUsage{"projects": {Hits: 2, Misses: 0, HitRate: 1}},
+	}
+
+	got := a.Add(b)
+	want := dt.UsageReport{
+		Requests: []dt.EndpointUsage{
+			{Method: "GET", Endpoint: "/v2/projects", Requests: 3},
+			{Method: "POST", Endpoint: "/v2/projects", Requests: 1},
+			{Method: "GET", Endpoint: "/v2/projects/*/devices", Requests: 3, Errors: 1},
+		},
+		TotalRequests:         7,
+		RateLimited:           1,
+		RetryAfterWaitSeconds: 1.5,
+		RateLimitWaitSeconds:  0.5,
+		TokenFetches:          1,
+		Caches:                map[string]dt.CacheUsage{"projects": {Hits: 3, Misses: 1, HitRate: 0.75}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Add() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package filelock takes exclusive locks on files, which lets the provider
+// processes that Terraform starts for validate, plan and apply share files
+// such as the token cache and the usage report.
+package filelock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// pollInterval is how often a locked file is polled.
+const pollInterval = 50 * time.Millisecond
+
+// Lock takes an exclusive lock on the file at path, creating it if it does not
+// exist, and waits until other processes release it or ctx is done. The
+// returned function releases the lock.
+func Lock(ctx context.Context, path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		if locked {
+			return func() {
+				_ = unlockFile(f)
+				f.Close()
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
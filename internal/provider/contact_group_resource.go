@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -43,7 +44,7 @@ func (r *contactGroupResource) Metadata(_ context.Context, req resource.Metadata
 
 // ImportState imports the state of a contact group resource.
 func (r *contactGroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseContactGroupName)
 }
 
 // Schema defines the schema for the resource.
@@ -111,10 +112,11 @@ func (r *contactGroupResource) Create(ctx context.Context, req resource.CreateRe
 	// Create the contact group using the api client
 	createdGroup, err := r.client.CreateContactGroup(ctx, createRequest)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to create contact group",
-			"An error occurred while creating the contact group: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to create contact group",
+			attribute: path.Root("organization"),
+			role:      roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -144,10 +146,15 @@ func (r *contactGroupResource) Read(ctx context.Context, req resource.ReadReques
 	// Get the contact group from the API
 	contactGroup, err := r.client.GetContactGroup(state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to read contact group",
-			"An error occurred while reading the contact group: "+err.Error(),
-		)
+		// The contact group was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Failed to read contact group",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -187,10 +194,10 @@ func (r *contactGroupResource) Update(ctx context.Context, req resource.UpdateRe
 	// Update the contact group using the API client
 	updatedGroup, err := r.client.UpdateContactGroup(ctx, updateRequest, plan.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to update contact group",
-			"An error occurred while updating the contact group: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Failed to update contact group",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -220,11 +227,15 @@ func (r *contactGroupResource) Delete(ctx context.Context, req resource.DeleteRe
 
 	// Delete the contact group using the API client
 	err := r.client.DeleteContactGroup(ctx, state.Name.ValueString())
+	// Nothing to do if it has already been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to delete contact group",
-			"An error occurred while deleting the contact group: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Failed to delete contact group",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 }
@@ -253,7 +264,7 @@ func (r *contactGroupResource) Configure(ctx context.Context, req resource.Confi
 
 func contactGroupToState(contactGroup dt.ContactGroup) (contactGroupResourceModel, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	organizationID, _, err := dt.ParseResourceName(contactGroup.Name)
+	contactGroupName, err := names.ParseContactGroupName(contactGroup.Name)
 	if err != nil {
 		diags = append(diags, diag.NewAttributeErrorDiagnostic(
 			path.Root("name"),
@@ -264,7 +275,7 @@ func contactGroupToState(contactGroup dt.ContactGroup) (contactGroupResourceMode
 
 	return contactGroupResourceModel{
 		Name:         types.StringValue(contactGroup.Name),
-		Organization: types.StringValue("organizations/" + organizationID),
+		Organization: types.StringValue(contactGroupName.OrganizationName().String()),
 		DisplayName:  types.StringValue(contactGroup.DisplayName),
 		Description:  types.StringValue(contactGroup.Description),
 		ContactCount: types.Int32Value(contactGroup.ContactCount),
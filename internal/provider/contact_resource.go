@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -44,7 +45,7 @@ func (r *contactResource) Metadata(_ context.Context, req resource.MetadataReque
 
 // ImportState imports the contact resource state.
 func (r *contactResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseContactName)
 }
 
 // Schema defines the schema for the resource
@@ -121,10 +122,11 @@ func (r *contactResource) Create(ctx context.Context, req resource.CreateRequest
 	// Create the contact using the client.
 	createdContact, err := r.client.CreateContact(ctx, createRequest)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to create contact",
-			"An error occurred while creating the contact: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to create contact",
+			attribute: path.Root("contact_group"),
+			role:      roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -155,10 +157,15 @@ func (r *contactResource) Read(ctx context.Context, req resource.ReadRequest, re
 	// Get the contact using the client.
 	contact, err := r.client.GetContact(state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to read contact",
-			"An error occurred while reading the contact: "+err.Error(),
-		)
+		// The contact was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Failed to read contact",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -193,10 +200,10 @@ func (r *contactResource) Update(ctx context.Context, req resource.UpdateRequest
 	// Update the contact using the client.
 	updatedContact, err := r.client.UpdateContact(ctx, updateRequest, plan.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to update contact",
-			"An error occurred while updating the contact: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Failed to update contact",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -227,11 +234,15 @@ func (r *contactResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	// Delete the contact using the client.
 	err := r.client.DeleteContact(ctx, state.Name.ValueString())
+	// Nothing to do if it has already been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Failed to delete contact",
-			"An error occurred while deleting the contact: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Failed to delete contact",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 }
@@ -271,7 +282,7 @@ func stateToContact(model contactResourceModel) dt.Contact {
 
 func contactToState(contact dt.Contact) (contactResourceModel, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	projectID, _, err := dt.ParseResourceName(contact.Name)
+	contactName, err := names.ParseContactName(contact.Name)
 	if err != nil {
 		diags = append(diags, diag.NewErrorDiagnostic(
 			"Failed to parse contact name",
@@ -280,7 +291,7 @@ func contactToState(contact dt.Contact) (contactResourceModel, diag.Diagnostics)
 	}
 	return contactResourceModel{
 		Name:             types.StringValue(contact.Name),
-		Project:          types.StringValue("projects/" + projectID),
+		Project:          types.StringValue(contactName.ProjectName().String()),
 		ContactGroup:     types.StringValue(contact.ContactGroup),
 		DisplayName:      types.StringValue(contact.DisplayName),
 		Email:            types.StringValue(contact.Email),
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// defaultProfile is the profile used in credentials files with profiles when no profile is selected.
+const defaultProfile = "default"
+
+// credentials is a service account key, and optionally the endpoints to use it with.
+type credentials struct {
+	KeyID         string `json:"key_id"`
+	KeySecret     string `json:"key_secret"`
+	Email         string `json:"email"`
+	TokenEndpoint string `json:"token_endpoint"`
+	URL           string `json:"url"`
+	EmulatorURL   string `json:"emulator_url"`
+}
+
+// UnmarshalJSON decodes credentials in the format of this provider, or in the
+// format of the service account keys downloaded from DT Studio:
+//
+//	{
+//	  "keyId": "...",
+//	  "secret": "...",
+//	  "email": "...@....serviceaccount.d21s.com"
+//	}
+func (c *credentials) UnmarshalJSON(data []byte) error {
+	// plain has the fields of credentials without this method.
+	type plain credentials
+	var key struct {
+		plain
+		StudioKeyID  string `json:"keyId"`
+		StudioSecret string `json:"secret"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return err
+	}
+	*c = credentials(key.plain)
+	c.KeyID = cmp.Or(c.KeyID, key.StudioKeyID)
+	c.KeySecret = cmp.Or(c.KeySecret, key.StudioSecret)
+	return nil
+}
+
+// credentialsFile is the format of credentials files with profiles. A file
+// either holds a single set of credentials at the top level, such as a key
+// downloaded from DT Studio or:
+//
+//	{
+//	  "key_id": "...",
+//	  "key_secret": "...",
+//	  "email": "...@....serviceaccount.d21s.com"
+//	}
+//
+// or named profiles, such as one for staging and one for production:
+//
+//	{
+//	  "profiles": {
+//	    "default": { "key_id": "...", "key_secret": "...", "email": "..." },
+//	    "staging": { "key_id": "...", "key_secret": "...", "email": "...", "url": "..." }
+//	  }
+//	}
+type credentialsFile struct {
+	Profiles map[string]credentials `json:"profiles"`
+}
+
+// defaultCredentialsFile returns the path of the credentials file used when a
+// profile is selected without a credentials file, dt/credentials.json in the
+// user configuration directory.
+func defaultCredentialsFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user configuration directory: %w", err)
+	}
+	return filepath.Join(dir, "dt", "credentials.json"), nil
+}
+
+// loadCredentials reads the credentials of the profile from the file. If
+// path is empty, the default credentials file is read. If profile is empty,
+// the top level credentials are used, or the default profile if the file
+// has profiles.
+func loadCredentials(path, profile string) (credentials, error) {
+	if path == "" {
+		var err error
+		if path, err = defaultCredentialsFile(); err != nil {
+			return credentials{}, err
+		}
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return credentials{}, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	var file credentialsFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return credentials{}, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+	}
+
+	if file.Profiles == nil {
+		if profile != "" {
+			return credentials{}, fmt.Errorf("credentials file %s has no profiles, but profile %q is selected", path, profile)
+		}
+		var creds credentials
+		if err := json.Unmarshal(content, &creds); err != nil {
+			return credentials{}, fmt.Errorf("failed to parse credentials file %s: %w", path, err)
+		}
+		return creds, nil
+	}
+
+	if profile == "" {
+		profile = defaultProfile
+	}
+	creds, ok := file.Profiles[profile]
+	if !ok {
+		names := slices.Sorted(maps.Keys(file.Profiles))
+		return credentials{}, fmt.Errorf("profile %q not found in credentials file %s, the available profiles are: %s", profile, path, strings.Join(names, ", "))
+	}
+	return creds, nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	t.Parallel()
+	const single = `{"key_id": "id", "key_secret": "secret", "email": "sa@example.com"}`
+	const profiles = `{
+		"profiles": {
+			"default": {"key_id": "default-id", "key_secret": "default-secret", "email": "default@example.com"},
+			"staging": {"key_id": "staging-id", "key_secret": "staging-secret", "email": "staging@example.com", "url": "https://staging.example.com"}
+		}
+	}`
+	tests := []struct {
+		name    string
+		content string
+		profile string
+		want    credentials
+		wantErr string
+	}{
+		{
+			name:    "single key",
+			content: single,
+			want:    credentials{KeyID: "id", KeySecret: "secret", Email: "sa@example.com"},
+		},
+		{
+			name:    "profile in single key file",
+			content: single,
+			profile: "staging",
+			wantErr: "has no profiles",
+		},
+		{
+			name:    "default profile",
+			content: profiles,
+			want:    credentials{KeyID: "default-id", KeySecret: "default-secret", Email: "default@example.com"},
+		},
+		{
+			name:    "named profile",
+			content: profiles,
+			profile: "staging",
+			want:    credentials{KeyID: "staging-id", KeySecret: "staging-secret", Email: "staging@example.com", URL: "https://staging.example.com"},
+		},
+		{
+			name:    "missing profile lists the available profiles",
+			content: profiles,
+			profile: "production",
+			wantErr: "the available profiles are: default, staging",
+		},
+		{
+			name:    "Studio key in a profile",
+			content: `{"profiles": {"default": {"keyId": "studio-id", "secret": "studio-secret", "email": "studio@example.com"}}}`,
+			want:    credentials{KeyID: "studio-id", KeySecret: "studio-secret", Email: "studio@example.com"},
+		},
+		{
+			name:    "invalid JSON",
+			content: `{"key_id": `,
+			wantErr: "failed to parse credentials file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "credentials.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := loadCredentials(path, tt.profile)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadCredentials() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadCredentials() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("loadCredentials() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsStudioKey(t *testing.T) {
+	t.Parallel()
+	got, err := loadCredentials("../../testdata/credentials/studio_key.json", "")
+	if err != nil {
+		t.Fatalf("loadCredentials() error = %v", err)
+	}
+	want := credentials{
+		KeyID:     "d0hjenj24tsg00b24tc0",
+		KeySecret: "3b4e6c1a9f2d4e7b8a0c5d6e7f8a9b0c",
+		Email:     "d0hjenj24tsg00b24tb0@cvinmt9aq9sc738g6ep0.serviceaccount.d21s.com",
+	}
+	if got != want {
+		t.Errorf("loadCredentials() = %+v, want %+v", got, want)
+	}
+}
+
+func TestLoadCredentialsMissingFile(t *testing.T) {
+	t.Parallel()
+	_, err := loadCredentials(filepath.Join(t.TempDir(), "missing.json"), "")
+	if err == nil || !strings.Contains(err.Error(), "failed to read credentials file") {
+		t.Errorf("loadCredentials() error = %v, want error reading the file", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -43,8 +44,7 @@ func (r *dataConnectorResource) Metadata(_ context.Context, req resource.Metadat
 }
 
 func (r *dataConnectorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseDataConnectorName)
 }
 
 // Schema defines the schema for the resource.
@@ -287,7 +287,11 @@ func (r *dataConnectorResource) Create(ctx context.Context, req resource.CreateR
 	// Create the data connector
 	created, err := r.client.CreateDataConnector(ctx, plan.Project.ValueString(), toBeCreated)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to create data connector", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "failed to create data connector",
+			attribute: path.Root("project"),
+			role:      roleProjectDeveloper,
+		}, err))
 		return
 	}
 
@@ -318,7 +322,15 @@ func (r *dataConnectorResource) Read(ctx context.Context, req resource.ReadReque
 	// Get the data connector from the API
 	dataConnector, err := r.client.GetDataConnector(ctx, state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("failed to get data connector", err.Error())
+		// The data connector was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to get data connector",
+			role:    roleProjectUser,
+		}, err))
 		return
 	}
 
@@ -348,8 +360,15 @@ func (r *dataConnectorResource) Delete(ctx context.Context, req resource.DeleteR
 
 	// Delete the data connector
 	err := r.client.DeleteDataConnector(ctx, state.Name.ValueString())
+	// Nothing to do if it has already been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("failed to delete data connector", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to delete data connector",
+			role:    roleProjectDeveloper,
+		}, err))
 		return
 	}
 }
@@ -373,7 +392,10 @@ func (r *dataConnectorResource) Update(ctx context.Context, req resource.UpdateR
 	// Update the data connector
 	dataConnector, err := r.client.UpdateDataConnector(ctx, dataConnector)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to update data connector", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to update data connector",
+			role:    roleProjectDeveloper,
+		}, err))
 		return
 	}
 
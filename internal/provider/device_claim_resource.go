@@ -0,0 +1,296 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource              = &deviceClaimResource{}
+	_ resource.ResourceWithConfigure = &deviceClaimResource{}
+)
+
+// NewDeviceClaimResource creates a new resource for claiming kits and devices into a project.
+func NewDeviceClaimResource() resource.Resource {
+	return &deviceClaimResource{}
+}
+
+// deviceClaimResource claims kits and devices into a project.
+type deviceClaimResource struct {
+	client *dt.Client
+}
+
+// Metadata returns the resource type name
+func (r *deviceClaimResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_claim"
+}
+
+// Schema defines the schema for the resource.
+func (r *deviceClaimResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Claims kits and devices into a project, like scanning their QR codes in DT Studio. Claimed devices can not be unclaimed, so destroying the resource leaves the devices in the project.",
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource name of the project to claim the kits and devices into. On the form `projects/{project_id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"kit_ids": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The IDs of the kits to claim, as printed on the kit.",
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Set{
+					setvalidator.AtLeastOneOf(path.MatchRoot("device_ids")),
+				},
+			},
+			"device_ids": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The IDs of the devices to claim, as printed on the device.",
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"devices": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "The resource names of the claimed devices that are still in the project, sorted. On the form `projects/{project_id}/devices/{device_id}`.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+type deviceClaimResourceModel struct {
+	Project   types.String `tfsdk:"project"`
+	KitIDs    types.Set    `tfsdk:"kit_ids"`
+	DeviceIDs types.Set    `tfsdk:"device_ids"`
+	Devices   types.List   `tfsdk:"devices"`
+}
+
+// Create claims the kits and devices into the project.
+func (r *deviceClaimResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceClaimResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var claim dt.ClaimDevicesRequest
+	var diags diag.Diagnostics
+	claim.KitIDs, diags = expandStringSet(ctx, plan.KitIDs)
+	resp.Diagnostics.Append(diags...)
+	claim.DeviceIDs, diags = expandStringSet(ctx, plan.DeviceIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	claimed, err := r.client.ClaimDevices(ctx, plan.Project.ValueString(), claim)
+	var deviceIDs []string
+	for _, device := range claimed {
+		deviceIDs = append(deviceIDs, device.DeviceID)
+	}
+	if err != nil {
+		// Devices that were claimed into the project by an earlier apply that
+		// failed, such as when another device could not be claimed, are
+		// claimed already.
+		alreadyClaimed, ok := r.alreadyClaimed(ctx, plan.Project.ValueString(), err)
+		if !ok {
+			resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+				summary:   "Failed to claim devices",
+				attribute: path.Root("project"),
+				role:      roleProjectAdmin,
+			}, err))
+			return
+		}
+		deviceIDs = append(deviceIDs, alreadyClaimed...)
+	}
+
+	plan.Devices, diags = deviceNamesList(ctx, plan.Project.ValueString(), deviceIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// alreadyClaimed returns the IDs of the devices, including the devices of
+// kits, that failed to be claimed because they are already in the project. It
+// returns false if anything else failed to be claimed.
+func (r *deviceClaimResource) alreadyClaimed(ctx context.Context, project string, err error) ([]string, bool) {
+	var claimErr *dt.ClaimError
+	if !errors.As(err, &claimErr) {
+		return nil, false
+	}
+	filter := dt.ListDevicesFilter{Project: project}
+	for _, kitErr := range claimErr.Kits {
+		if kitErr.Code != dt.ClaimErrorAlreadyClaimed {
+			return nil, false
+		}
+		kit, err := r.client.GetKit(ctx, kitErr.KitID)
+		if err != nil {
+			return nil, false
+		}
+		for _, device := range kit.Devices {
+			filter.DeviceIDs = append(filter.DeviceIDs, device.DeviceID)
+		}
+	}
+	for _, deviceErr := range claimErr.Devices {
+		if deviceErr.Code != dt.ClaimErrorAlreadyClaimed {
+			return nil, false
+		}
+		filter.DeviceIDs = append(filter.DeviceIDs, deviceErr.DeviceID)
+	}
+	// A device can be claimed both by its device ID and as part of a kit.
+	slices.Sort(filter.DeviceIDs)
+	filter.DeviceIDs = slices.Compact(filter.DeviceIDs)
+
+	var deviceIDs []string
+	for device, err := range r.client.ListDevices(ctx, filter) {
+		if err != nil {
+			return nil, false
+		}
+		deviceName, err := names.ParseDeviceName(device.Name)
+		if err != nil {
+			return nil, false
+		}
+		deviceIDs = append(deviceIDs, deviceName.Device)
+	}
+	return deviceIDs, len(deviceIDs) == len(filter.DeviceIDs)
+}
+
+// Read refreshes the claimed devices that are still in the project, since
+// they can be transferred to other projects after they are claimed.
+func (r *deviceClaimResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceClaimResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	devices, diags := expandStringList(ctx, state.Devices)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	filter := dt.ListDevicesFilter{Project: state.Project.ValueString()}
+	for _, device := range devices {
+		deviceName, err := names.ParseDeviceName(device)
+		if err != nil {
+			resp.Diagnostics.AddError("failed to get device ID and project ID", err.Error())
+			return
+		}
+		filter.DeviceIDs = append(filter.DeviceIDs, deviceName.Device)
+	}
+
+	var deviceIDs []string
+	if len(filter.DeviceIDs) > 0 {
+		for device, err := range r.client.ListDevices(ctx, filter) {
+			// The project was deleted outside of Terraform, remove it from the state.
+			if dt.IsNotFound(err) {
+				resp.State.RemoveResource(ctx)
+				return
+			}
+			if err != nil {
+				resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+					summary:   "Failed to read claimed devices",
+					attribute: path.Root("project"),
+					role:      roleProjectUser,
+				}, err))
+				return
+			}
+			deviceName, err := names.ParseDeviceName(device.Name)
+			if err != nil {
+				resp.Diagnostics.AddError("failed to get device ID and project ID", err.Error())
+				return
+			}
+			deviceIDs = append(deviceIDs, deviceName.Device)
+		}
+	}
+
+	state.Devices, diags = deviceNamesList(ctx, state.Project.ValueString(), deviceIDs)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update is never called, since every attribute that can be configured
+// requires the resource to be replaced.
+func (r *deviceClaimResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan deviceClaimResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the resource from the state. Devices can not be
+// unclaimed through the API, so they are left in the project.
+func (r *deviceClaimResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// deviceNamesList returns the sorted resource names of the devices in the project.
+func deviceNamesList(ctx context.Context, project string, deviceIDs []string) (types.List, diag.Diagnostics) {
+	projectName, err := names.ParseProjectName(project)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddAttributeError(path.Root("project"), "Invalid project name", err.Error())
+		return types.ListNull(types.StringType), diags
+	}
+	deviceNames := make([]string, 0, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		deviceNames = append(deviceNames, names.DeviceName{Project: projectName.Project, Device: deviceID}.String())
+	}
+	slices.Sort(deviceNames)
+	return types.ListValueFrom(ctx, types.StringType, slices.Compact(deviceNames))
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *deviceClaimResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*dt.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *dt.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceClaimResource(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read testing
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_claim/kit_and_device.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_claim.test", "devices.#", "3"),
+					resource.TestCheckResourceAttr("dt_device_claim.test", "devices.0", "projects/d2m1a0bp4ab4c73e0c0g/devices/d2m1a4rp4ab4c73e0c1g"),
+					resource.TestCheckResourceAttr("dt_device_claim.test", "devices.2", "projects/d2m1a0bp4ab4c73e0c0g/devices/d2m1a6bp4ab4c73e0c2g"),
+					resource.TestCheckResourceAttr("dt_notification_rule.test", "devices.#", "3"),
+				),
+			},
+			// Claiming a device that does not exist fails
+			{
+				Config: providerConfig + `resource "dt_device_claim" "missing" {
+					project    = "projects/d2m1a0bp4ab4c73e0c0g"
+					device_ids = ["no-such-device"]
+				}`,
+				ExpectError: regexp.MustCompile("Failed to claim devices"),
+			},
+			// Either kits or devices must be claimed
+			{
+				Config: providerConfig + `resource "dt_device_claim" "empty" {
+					project = "projects/d2m1a0bp4ab4c73e0c0g"
+				}`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+		},
+	})
+}
+
+func TestAccDeviceClaimResourceReclaimKit(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Claim the kit
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_claim/reclaim_kit.tf"),
+				Check:  resource.TestCheckResourceAttr("dt_device_claim.first", "devices.#", "2"),
+			},
+			// Re-apply after the state of the claim was lost, which leaves the
+			// kit in the project and makes it already claimed
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_claim/reclaim_kit_lost_state.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_claim.second", "devices.#", "3"),
+					resource.TestCheckResourceAttr("dt_device_claim.second", "devices.0", "projects/d2m1a0bp4ab4c73e0c0g/devices/d2m1a7rp4ab4c73e0c30"),
+					resource.TestCheckResourceAttr("dt_device_claim.second", "devices.2", "projects/d2m1a0bp4ab4c73e0c0g/devices/d2m1a93p4ab4c73e0c40"),
+				),
+			},
+			// An already claimed kit in another project still fails
+			{
+				Config: providerConfig + `resource "dt_device_claim" "other_project" {
+					project = "projects/d2m0j2rp4ab4c73e0b2g"
+					kit_ids = ["acc-test-reclaim-kit"]
+				}`,
+				ExpectError: regexp.MustCompile("Failed to claim devices"),
+			},
+		},
+	})
+}
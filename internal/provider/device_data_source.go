@@ -5,11 +5,14 @@ package provider
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -57,16 +60,255 @@ func (d deviceDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Description: "The labels of the device.",
 				ElementType: types.StringType,
 			},
+			"product_number": schema.StringAttribute{
+				Computed:    true,
+				Description: "The product number of the device, which identifies its hardware.",
+			},
+			"update_time": schema.StringAttribute{
+				Computed:    true,
+				Description: "When the device last reported its state, as an RFC 3339 timestamp. Null if it has not reported anything.",
+			},
+			"battery_percentage": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The remaining battery of a sensor, in percent. Null for cloud connectors, and sensors that have not reported it.",
+			},
+			"network_status": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "How well the last event of a sensor reached the cloud connectors. Null for cloud connectors, and sensors that have not sent any events.",
+				Attributes: map[string]schema.Attribute{
+					"signal_strength": schema.Int64Attribute{
+						Computed:    true,
+						Description: "The signal strength, in percent, of the strongest cloud connector.",
+					},
+					"rssi": schema.Int64Attribute{
+						Computed:    true,
+						Description: "The received signal strength indicator of the strongest cloud connector, in dBm.",
+					},
+					"transmission_mode": schema.StringAttribute{
+						Computed:    true,
+						Description: "The transmission mode of the sensor, `LOW_POWER_STANDARD_MODE` or `HIGH_POWER_BOOST_MODE`.",
+					},
+					"cloud_connectors": schema.ListNestedAttribute{
+						Computed:    true,
+						Description: "The cloud connectors that received the event.",
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"id": schema.StringAttribute{
+									Computed:    true,
+									Description: "The device ID of the cloud connector.",
+								},
+								"signal_strength": schema.Int64Attribute{
+									Computed:    true,
+									Description: "The signal strength, in percent.",
+								},
+								"rssi": schema.Int64Attribute{
+									Computed:    true,
+									Description: "The received signal strength indicator, in dBm.",
+								},
+							},
+						},
+					},
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"connection_status": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "How a cloud connector is connected to the cloud. Null for sensors.",
+				Attributes: map[string]schema.Attribute{
+					"connection": schema.StringAttribute{
+						Computed:    true,
+						Description: "The current connection, `ETHERNET`, `CELLULAR` or `OFFLINE`.",
+					},
+					"available": schema.ListAttribute{
+						Computed:    true,
+						ElementType: types.StringType,
+						Description: "The connections that are available.",
+					},
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"temperature": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The last temperature reported by a temperature sensor. Null for other devices.",
+				Attributes: map[string]schema.Attribute{
+					"value": schema.Float64Attribute{
+						Computed:    true,
+						Description: "The temperature in degrees Celsius.",
+					},
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"humidity": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The last humidity reported by a humidity sensor. Null for other devices.",
+				Attributes: map[string]schema.Attribute{
+					"temperature": schema.Float64Attribute{
+						Computed:    true,
+						Description: "The temperature in degrees Celsius.",
+					},
+					"relative_humidity": schema.Float64Attribute{
+						Computed:    true,
+						Description: "The relative humidity in percent.",
+					},
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"co2": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The last CO2 concentration reported by a CO2 sensor. Null for other devices.",
+				Attributes: map[string]schema.Attribute{
+					"ppm": schema.Int64Attribute{
+						Computed:    true,
+						Description: "The CO2 concentration in parts per million.",
+					},
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"pressure": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The last barometric pressure reported by a CO2 sensor. Null for other devices.",
+				Attributes: map[string]schema.Attribute{
+					"pascal": schema.Float64Attribute{
+						Computed:    true,
+						Description: "The pressure in pascal.",
+					},
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"object_present": reportedStateAttribute("The last state reported by a proximity sensor, `PRESENT` or `NOT_PRESENT`."),
+			"water_present":  reportedStateAttribute("The last state reported by a water detector, `PRESENT` or `NOT_PRESENT`."),
+			"motion":         reportedStateAttribute("The last state reported by a motion sensor, `MOTION_DETECTED` or `NO_MOTION_DETECTED`."),
+			"desk_occupancy": reportedStateAttribute("The last state reported by a desk occupancy sensor, `OCCUPIED` or `NOT_OCCUPIED`."),
+			"contact":        reportedStateAttribute("The last state reported by a contact sensor, `OPEN` or `CLOSED`."),
+			"touch": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The last touch of a touch sensor. Null for other devices.",
+				Attributes: map[string]schema.Attribute{
+					"update_time": updateTimeAttribute(),
+				},
+			},
+			"touch_count":          reportedCountAttribute("The number of times a touch counter has been touched."),
+			"object_present_count": reportedCountAttribute("The number of times a proximity counter has detected an object."),
+		},
+	}
+}
+
+// updateTimeAttribute returns the schema of the update time of a reported state.
+func updateTimeAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Computed:    true,
+		Description: "When the state was reported, as an RFC 3339 timestamp.",
+	}
+}
+
+// reportedStateAttribute returns the schema of a reported state that is one of a few values.
+func reportedStateAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Computed:    true,
+		Description: description + " Null for other devices.",
+		Attributes: map[string]schema.Attribute{
+			"state": schema.StringAttribute{
+				Computed:    true,
+				Description: "The reported state.",
+			},
+			"update_time": updateTimeAttribute(),
+		},
+	}
+}
+
+// reportedCountAttribute returns the schema of a reported count.
+func reportedCountAttribute(description string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Computed:    true,
+		Description: description + " Null for other devices.",
+		Attributes: map[string]schema.Attribute{
+			"total": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The total count.",
+			},
+			"update_time": updateTimeAttribute(),
 		},
 	}
 }
 
 type DeviceDataSourceModel struct {
-	DeviceID  types.String `tfsdk:"device_id"`
-	ProjectID types.String `tfsdk:"project_id"`
-	Name      types.String `tfsdk:"name"`
-	Type      types.String `tfsdk:"type"`
-	Labels    types.Map    `tfsdk:"labels"`
+	DeviceID           types.String                 `tfsdk:"device_id"`
+	ProjectID          types.String                 `tfsdk:"project_id"`
+	Name               types.String                 `tfsdk:"name"`
+	Type               types.String                 `tfsdk:"type"`
+	Labels             types.Map                    `tfsdk:"labels"`
+	ProductNumber      types.String                 `tfsdk:"product_number"`
+	UpdateTime         types.String                 `tfsdk:"update_time"`
+	BatteryPercentage  types.Int64                  `tfsdk:"battery_percentage"`
+	NetworkStatus      *deviceNetworkStatusModel    `tfsdk:"network_status"`
+	ConnectionStatus   *deviceConnectionStatusModel `tfsdk:"connection_status"`
+	Temperature        *deviceTemperatureModel      `tfsdk:"temperature"`
+	Humidity           *deviceHumidityModel         `tfsdk:"humidity"`
+	CO2                *deviceCO2Model              `tfsdk:"co2"`
+	Pressure           *devicePressureModel         `tfsdk:"pressure"`
+	ObjectPresent      *deviceStateModel            `tfsdk:"object_present"`
+	WaterPresent       *deviceStateModel            `tfsdk:"water_present"`
+	Motion             *deviceStateModel            `tfsdk:"motion"`
+	DeskOccupancy      *deviceStateModel            `tfsdk:"desk_occupancy"`
+	Contact            *deviceStateModel            `tfsdk:"contact"`
+	Touch              *deviceTouchModel            `tfsdk:"touch"`
+	TouchCount         *deviceCountModel            `tfsdk:"touch_count"`
+	ObjectPresentCount *deviceCountModel            `tfsdk:"object_present_count"`
+}
+
+type deviceNetworkStatusModel struct {
+	SignalStrength   types.Int64                 `tfsdk:"signal_strength"`
+	RSSI             types.Int64                 `tfsdk:"rssi"`
+	TransmissionMode types.String                `tfsdk:"transmission_mode"`
+	CloudConnectors  []deviceCloudConnectorModel `tfsdk:"cloud_connectors"`
+	UpdateTime       types.String                `tfsdk:"update_time"`
+}
+
+type deviceCloudConnectorModel struct {
+	ID             types.String `tfsdk:"id"`
+	SignalStrength types.Int64  `tfsdk:"signal_strength"`
+	RSSI           types.Int64  `tfsdk:"rssi"`
+}
+
+type deviceConnectionStatusModel struct {
+	Connection types.String `tfsdk:"connection"`
+	Available  types.List   `tfsdk:"available"`
+	UpdateTime types.String `tfsdk:"update_time"`
+}
+
+type deviceTemperatureModel struct {
+	Value      types.Float64 `tfsdk:"value"`
+	UpdateTime types.String  `tfsdk:"update_time"`
+}
+
+type deviceHumidityModel struct {
+	Temperature      types.Float64 `tfsdk:"temperature"`
+	RelativeHumidity types.Float64 `tfsdk:"relative_humidity"`
+	UpdateTime       types.String  `tfsdk:"update_time"`
+}
+
+type deviceCO2Model struct {
+	PPM        types.Int64  `tfsdk:"ppm"`
+	UpdateTime types.String `tfsdk:"update_time"`
+}
+
+type devicePressureModel struct {
+	Pascal     types.Float64 `tfsdk:"pascal"`
+	UpdateTime types.String  `tfsdk:"update_time"`
+}
+
+type deviceStateModel struct {
+	State      types.String `tfsdk:"state"`
+	UpdateTime types.String `tfsdk:"update_time"`
+}
+
+type deviceTouchModel struct {
+	UpdateTime types.String `tfsdk:"update_time"`
+}
+
+type deviceCountModel struct {
+	Total      types.Int64  `tfsdk:"total"`
+	UpdateTime types.String `tfsdk:"update_time"`
 }
 
 func (d deviceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -80,11 +322,15 @@ func (d deviceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	device, err := d.client.GetDevice(ctx, config.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("failed to get device", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "failed to get device",
+			attribute: path.Root("name"),
+			role:      roleProjectUser,
+		}, err))
 		return
 	}
 
-	deviceID, projectID, err := idFromName(device.Name)
+	deviceName, err := names.ParseDeviceName(device.Name)
 	if err != nil {
 		resp.Diagnostics.AddError("failed to get device ID and project ID", err.Error())
 		return
@@ -96,11 +342,18 @@ func (d deviceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 
 	state := DeviceDataSourceModel{
-		DeviceID:  types.StringValue(deviceID),
-		ProjectID: types.StringValue(projectID),
-		Name:      types.StringValue(device.Name),
-		Type:      types.StringValue(device.Type),
-		Labels:    labels,
+		DeviceID:          types.StringValue(deviceName.Device),
+		ProjectID:         types.StringValue(deviceName.Project),
+		Name:              types.StringValue(device.Name),
+		Type:              types.StringValue(device.Type),
+		Labels:            labels,
+		ProductNumber:     types.StringValue(device.ProductNumber),
+		UpdateTime:        types.StringNull(),
+		BatteryPercentage: types.Int64Null(),
+	}
+	resp.Diagnostics.Append(state.setReported(ctx, device.Reported)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	diag = resp.State.Set(ctx, &state)
@@ -110,6 +363,87 @@ func (d deviceDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	}
 }
 
+// setReported sets the attributes of the reported state of the device. The
+// states that the device has not reported are left null.
+func (m *DeviceDataSourceModel) setReported(ctx context.Context, reported *dt.Reported) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if reported == nil {
+		return diags
+	}
+	if updateTime := reported.LastUpdateTime(); !updateTime.IsZero() {
+		m.UpdateTime = types.StringValue(updateTime.Format(time.RFC3339Nano))
+	}
+	if r := reported.BatteryStatus; r != nil {
+		m.BatteryPercentage = types.Int64Value(int64(r.Percentage))
+	}
+	if r := reported.NetworkStatus; r != nil {
+		m.NetworkStatus = &deviceNetworkStatusModel{
+			SignalStrength:   types.Int64Value(int64(r.SignalStrength)),
+			RSSI:             types.Int64Value(int64(r.RSSI)),
+			TransmissionMode: types.StringValue(r.TransmissionMode),
+			CloudConnectors:  []deviceCloudConnectorModel{},
+			UpdateTime:       types.StringValue(r.UpdateTime),
+		}
+		for _, connector := range r.CloudConnectors {
+			m.NetworkStatus.CloudConnectors = append(m.NetworkStatus.CloudConnectors, deviceCloudConnectorModel{
+				ID:             types.StringValue(connector.ID),
+				SignalStrength: types.Int64Value(int64(connector.SignalStrength)),
+				RSSI:           types.Int64Value(int64(connector.RSSI)),
+			})
+		}
+	}
+	if r := reported.ConnectionStatus; r != nil {
+		available, d := types.ListValueFrom(ctx, types.StringType, r.Available)
+		diags.Append(d...)
+		m.ConnectionStatus = &deviceConnectionStatusModel{
+			Connection: types.StringValue(r.Connection),
+			Available:  available,
+			UpdateTime: types.StringValue(r.UpdateTime),
+		}
+	}
+	if r := reported.Temperature; r != nil {
+		m.Temperature = &deviceTemperatureModel{Value: types.Float64Value(r.Value), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.Humidity; r != nil {
+		m.Humidity = &deviceHumidityModel{
+			Temperature:      types.Float64Value(r.Temperature),
+			RelativeHumidity: types.Float64Value(r.RelativeHumidity),
+			UpdateTime:       types.StringValue(r.UpdateTime),
+		}
+	}
+	if r := reported.CO2; r != nil {
+		m.CO2 = &deviceCO2Model{PPM: types.Int64Value(int64(r.PPM)), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.Pressure; r != nil {
+		m.Pressure = &devicePressureModel{Pascal: types.Float64Value(r.Pascal), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.ObjectPresent; r != nil {
+		m.ObjectPresent = &deviceStateModel{State: types.StringValue(r.State), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.WaterPresent; r != nil {
+		m.WaterPresent = &deviceStateModel{State: types.StringValue(r.State), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.Motion; r != nil {
+		m.Motion = &deviceStateModel{State: types.StringValue(r.State), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.DeskOccupancy; r != nil {
+		m.DeskOccupancy = &deviceStateModel{State: types.StringValue(r.State), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.Contact; r != nil {
+		m.Contact = &deviceStateModel{State: types.StringValue(r.State), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.Touch; r != nil {
+		m.Touch = &deviceTouchModel{UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.TouchCount; r != nil {
+		m.TouchCount = &deviceCountModel{Total: types.Int64Value(int64(r.Total)), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	if r := reported.ObjectPresentCount; r != nil {
+		m.ObjectPresentCount = &deviceCountModel{Total: types.Int64Value(int64(r.Total)), UpdateTime: types.StringValue(r.UpdateTime)}
+	}
+	return diags
+}
+
 func (d *deviceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Add a nil check when handling ProviderData because Terraform
 	// sets that data after it calls the ConfigureProvider RPC.
@@ -128,11 +462,3 @@ func (d *deviceDataSource) Configure(ctx context.Context, req datasource.Configu
 
 	d.client = *client
 }
-
-func idFromName(name string) (string, string, error) {
-	parts := strings.Split(name, "/")
-	if len(parts) != 4 {
-		return name, "", fmt.Errorf("invalid device name: %s", name)
-	}
-	return parts[len(parts)-1], parts[len(parts)-3], nil
-}
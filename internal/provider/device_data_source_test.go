@@ -25,6 +25,43 @@ func TestAccSafeDeviceDataSource(t *testing.T) {
 					resource.TestCheckResourceAttr("data.dt_device.test", "type", "temperature"),
 					resource.TestCheckResourceAttr("data.dt_device.test", "labels.%", "2"),
 					resource.TestCheckResourceAttr("data.dt_device.test", "labels.virtual-sensor", ""),
+					// The reported state of a temperature sensor.
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "update_time"),
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "battery_percentage"),
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "network_status.signal_strength"),
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "network_status.cloud_connectors.0.id"),
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "temperature.value"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "connection_status.connection"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "object_present.state"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "contact.state"),
+				),
+			},
+			// Read a cloud connector
+			{
+				Config: providerConfig + `data "dt_device" "test" {name = "projects/d0919uq3tjjs739bf18g/devices/emud091aassh1nc738nel0g"}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device.test", "type", "ccon"),
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "connection_status.connection"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "battery_percentage"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "temperature.value"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDeviceDataSourceContact(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The state of a contact sensor is read as contact, not object_present
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device/contact.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device.test", "contact.state", "OPEN"),
+					resource.TestCheckResourceAttrSet("data.dt_device.test", "contact.update_time"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "object_present.state"),
 				),
 			},
 		},
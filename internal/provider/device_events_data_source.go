@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &deviceEventsDataSource{}
+	_ datasource.DataSourceWithConfigure = &deviceEventsDataSource{}
+)
+
+// defaultEventsLimit is the number of events read when limit is not set.
+const defaultEventsLimit = 10
+
+func NewDeviceEventsDataSource() datasource.DataSource {
+	return &deviceEventsDataSource{}
+}
+
+type deviceEventsDataSource struct {
+	client dt.Client
+}
+
+// Metadata returns the data source type name.
+func (d *deviceEventsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_events"
+}
+
+// eventAttributes are the attributes of an event in events and latest.
+func eventAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"event_id": schema.StringAttribute{
+			Computed:    true,
+			Description: "The ID of the event.",
+		},
+		"event_type": schema.StringAttribute{
+			Computed:    true,
+			Description: "The type of the event, such as `temperature` or `networkStatus`.",
+		},
+		"timestamp": schema.StringAttribute{
+			Computed:    true,
+			Description: "When the event was published, in RFC 3339 format.",
+		},
+		"data": schema.StringAttribute{
+			Computed:    true,
+			Description: "The data of the event as JSON, with a single field named after the event type. Use `jsondecode` to read it, such as `jsondecode(event.data).temperature.value`.",
+		},
+	}
+}
+
+// Schema defines the schema for the data source.
+func (d *deviceEventsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Reads the latest events of a device, such as the readings of a sensor or the events published by an emulator.",
+		Attributes: map[string]schema.Attribute{
+			"device": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource name of the device. On the form `projects/{project_id}/devices/{device_id}`.",
+			},
+			"event_types": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only read events of these types, such as `temperature` or `touch`.",
+			},
+			"start_time": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only read events published at or after this time, in RFC 3339 format.",
+			},
+			"end_time": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only read events published at or before this time, in RFC 3339 format.",
+			},
+			"limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The largest number of events to read. Defaults to %d.", defaultEventsLimit),
+				Validators: []validator.Int64{
+					int64validator.Between(1, dt.MaxPageSize),
+				},
+			},
+			"events": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The latest events that match the filters, newest first.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: eventAttributes(),
+				},
+			},
+			"latest": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "The latest event that matches the filters. Null if there are none.",
+				Attributes:  eventAttributes(),
+			},
+		},
+	}
+}
+
+type deviceEventsDataSourceModel struct {
+	Device     types.String `tfsdk:"device"`
+	EventTypes types.Set    `tfsdk:"event_types"`
+	StartTime  types.String `tfsdk:"start_time"`
+	EndTime    types.String `tfsdk:"end_time"`
+	Limit      types.Int64  `tfsdk:"limit"`
+	Events     []eventModel `tfsdk:"events"`
+	Latest     types.Object `tfsdk:"latest"`
+}
+
+type eventModel struct {
+	EventID   types.String `tfsdk:"event_id"`
+	EventType types.String `tfsdk:"event_type"`
+	Timestamp types.String `tfsdk:"timestamp"`
+	Data      types.String `tfsdk:"data"`
+}
+
+var eventAttributeTypes = map[string]attr.Type{
+	"event_id":   types.StringType,
+	"event_type": types.StringType,
+	"timestamp":  types.StringType,
+	"data":       types.StringType,
+}
+
+func (d *deviceEventsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// retrieve data source configuration
+	var config deviceEventsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var filter dt.ListEventsFilter
+	resp.Diagnostics.Append(config.EventTypes.ElementsAs(ctx, &filter.EventTypes, false)...)
+	for attribute, value := range map[string]types.String{"start_time": config.StartTime, "end_time": config.EndTime} {
+		if value.IsNull() {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root(attribute), "Invalid time", fmt.Sprintf("%s must be in RFC 3339 format, such as 2025-03-12T09:00:00Z: %s", attribute, err))
+			continue
+		}
+		if attribute == "start_time" {
+			filter.StartTime = t
+		} else {
+			filter.EndTime = t
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	limit := int64(defaultEventsLimit)
+	if !config.Limit.IsNull() {
+		limit = config.Limit.ValueInt64()
+	}
+	// Request the events in a single page, as the limit is at most MaxPageSize.
+	filter.PageSize = int(limit)
+
+	config.Events = []eventModel{}
+	for event, err := range d.client.ListEvents(ctx, config.Device.ValueString(), filter) {
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+				summary:   "failed to list events",
+				attribute: path.Root("device"),
+				role:      roleProjectUser,
+			}, err))
+			return
+		}
+		config.Events = append(config.Events, eventModel{
+			EventID:   types.StringValue(event.EventID),
+			EventType: types.StringValue(event.EventType),
+			Timestamp: types.StringValue(event.Timestamp),
+			Data:      types.StringValue(string(event.Data)),
+		})
+		if int64(len(config.Events)) >= limit {
+			break
+		}
+	}
+
+	config.Latest = types.ObjectNull(eventAttributeTypes)
+	if len(config.Events) > 0 {
+		latest, diags := types.ObjectValueFrom(ctx, eventAttributeTypes, config.Events[0])
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		config.Latest = latest
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+func (d *deviceEventsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*dt.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"invalid provider data",
+			fmt.Sprintf("Expected *dt.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = *client
+}
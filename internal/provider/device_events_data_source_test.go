@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceEventsDataSource(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The latest events, newest first
+			{
+				Config: providerConfig + `data "dt_device_events" "test" {
+					device = "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"
+					limit  = 2
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.0.timestamp", "2025-03-12T09:21:07.137Z"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.1.timestamp", "2025-03-12T09:21:07.137Z"),
+					resource.TestCheckResourceAttrSet("data.dt_device_events.test", "latest.event_id"),
+				),
+			},
+			// The latest event of a type, in a time window
+			{
+				Config: providerConfig + `data "dt_device_events" "test" {
+					device      = "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"
+					event_types = ["temperature"]
+					end_time    = "2025-03-12T09:10:00Z"
+				}
+				output "value" {
+					value = jsondecode(data.dt_device_events.test.latest.data).temperature.value
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "latest.event_type", "temperature"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "latest.timestamp", "2025-03-12T09:01:07.137Z"),
+					resource.TestCheckOutput("value", "20.5"),
+				),
+			},
+			// No matching events
+			{
+				Config: providerConfig + `data "dt_device_events" "test" {
+					device      = "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"
+					event_types = ["touch"]
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.#", "0"),
+					resource.TestCheckNoResourceAttr("data.dt_device_events.test", "latest.event_id"),
+				),
+			},
+			// Invalid time
+			{
+				Config: providerConfig + `data "dt_device_events" "test" {
+					device     = "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"
+					start_time = "yesterday"
+				}`,
+				ExpectError: regexp.MustCompile("Invalid time"),
+			},
+		},
+	})
+}
@@ -0,0 +1,281 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                = &deviceLabelsResource{}
+	_ resource.ResourceWithConfigure   = &deviceLabelsResource{}
+	_ resource.ResourceWithImportState = &deviceLabelsResource{}
+)
+
+// systemDeviceLabels are the labels that DT sets on devices, such as the
+// display name of the device in DT Studio. They are never removed by the
+// dt_device_labels resource, even if they are set with it.
+var systemDeviceLabels = []string{"name", "virtual-sensor"}
+
+// NewDeviceLabelsResource creates a new resource for managing the labels of a device.
+func NewDeviceLabelsResource() resource.Resource {
+	return &deviceLabelsResource{}
+}
+
+// deviceLabelsResource manages the labels of an existing device.
+type deviceLabelsResource struct {
+	client *dt.Client
+}
+
+// Metadata returns the resource type name
+func (r *deviceLabelsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_labels"
+}
+
+// ImportState imports the labels of a device by the resource name of the
+// device. All the labels of the device, except for the system labels, are
+// imported.
+func (r *deviceLabelsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importByName(ctx, req, resp, names.ParseDeviceName)
+}
+
+// Schema defines the schema for the resource.
+func (r *deviceLabelsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the labels of an existing device, such as a physical sensor. The system labels `name` and `virtual-sensor` are never removed, but the `name` label can be set to change the display name of the device.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource name of the device. On the form `projects/{project_id}/devices/{device_id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"labels": schema.MapAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "The labels to set on the device.",
+			},
+			"authoritative": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether `labels` are the only labels of the device. If true, the other labels of the device are removed, except for the system labels. If false, only the keys in `labels` are managed, and the other labels are left as they are. Defaults to false.",
+			},
+		},
+	}
+}
+
+type deviceLabelsResourceModel struct {
+	Name          types.String `tfsdk:"name"`
+	Labels        types.Map    `tfsdk:"labels"`
+	Authoritative types.Bool   `tfsdk:"authoritative"`
+}
+
+// Create sets the labels of the device.
+func (r *deviceLabelsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceLabelsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, plan, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *deviceLabelsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceLabelsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	device, err := r.client.GetDevice(ctx, state.Name.ValueString())
+	if err != nil {
+		// The device was deleted outside of Terraform, remove it from the state.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to read device labels",
+			attribute: path.Root("name"),
+			role:      roleProjectUser,
+		}, err))
+		return
+	}
+
+	// The labels are null after an import, and all the labels of the device
+	// are imported.
+	imported := state.Labels.IsNull()
+	if state.Authoritative.IsNull() {
+		state.Authoritative = types.BoolValue(false)
+	}
+	var owned map[string]string
+	resp.Diagnostics.Append(state.Labels.ElementsAs(ctx, &owned, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Keep the labels the resource owns, so that the ones that were removed
+	// or changed outside of Terraform are set again.
+	labels := make(map[string]string)
+	for key, value := range device.Labels {
+		_, isOwned := owned[key]
+		if isOwned || ((imported || state.Authoritative.ValueBool()) && !slices.Contains(systemDeviceLabels, key)) {
+			labels[key] = value
+		}
+	}
+	labelsValue, diags := types.MapValueFrom(ctx, types.StringType, labels)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	state.Labels = labelsValue
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update sets the labels in the plan, and removes the labels that are no
+// longer in it.
+func (r *deviceLabelsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state deviceLabelsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var previous map[string]string
+	resp.Diagnostics.Append(state.Labels.ElementsAs(ctx, &previous, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, plan, previous)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete removes the labels of the resource from the device. The other
+// labels of the device are left as they are.
+func (r *deviceLabelsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state deviceLabelsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var owned map[string]string
+	resp.Diagnostics.Append(state.Labels.ElementsAs(ctx, &owned, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var remove []string
+	for key := range owned {
+		if !slices.Contains(systemDeviceLabels, key) {
+			remove = append(remove, key)
+		}
+	}
+	slices.Sort(remove)
+
+	err := r.client.UpdateDeviceLabels(ctx, state.Name.ValueString(), nil, remove)
+	// Nothing to do if the device has been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to remove device labels",
+			attribute: path.Root("name"),
+			role:      roleProjectDeveloper,
+		}, err))
+	}
+}
+
+// apply sets the labels of the plan on the device, and removes the previous
+// labels of the resource that are no longer in the plan. If the plan is
+// authoritative, the other labels of the device are removed too. System
+// labels are never removed.
+func (r *deviceLabelsResource) apply(ctx context.Context, plan deviceLabelsResourceModel, previous map[string]string) diag.Diagnostics {
+	var labels map[string]string
+	diags := plan.Labels.ElementsAs(ctx, &labels, false)
+	if diags.HasError() {
+		return diags
+	}
+
+	candidates := make(map[string]bool)
+	for key := range previous {
+		candidates[key] = true
+	}
+	if plan.Authoritative.ValueBool() {
+		device, err := r.client.GetDevice(ctx, plan.Name.ValueString())
+		if err != nil {
+			diags.Append(clientErrorDiagnostic(clientError{
+				summary:   "Failed to read device labels",
+				attribute: path.Root("name"),
+				role:      roleProjectUser,
+			}, err))
+			return diags
+		}
+		for key := range device.Labels {
+			candidates[key] = true
+		}
+	}
+	var remove []string
+	for key := range candidates {
+		if _, ok := labels[key]; !ok && !slices.Contains(systemDeviceLabels, key) {
+			remove = append(remove, key)
+		}
+	}
+	slices.Sort(remove)
+
+	if err := r.client.UpdateDeviceLabels(ctx, plan.Name.ValueString(), labels, remove); err != nil {
+		diags.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to update device labels",
+			attribute: path.Root("name"),
+			role:      roleProjectDeveloper,
+		}, err))
+	}
+	return diags
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *deviceLabelsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*dt.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *dt.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+func TestAccDeviceLabelsResource(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read testing
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_labels/owned.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_labels.test", "labels.%", "2"),
+					resource.TestCheckResourceAttr("dt_device_labels.test", "labels.room", "freezer"),
+					resource.TestCheckResourceAttr("dt_device_labels.test", "authoritative", "false"),
+					// The system labels and the labels set outside of Terraform are kept.
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.%", "5"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.name", "labelled sensor"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.virtual-sensor", ""),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.owner", "facilities"),
+				),
+			},
+			// Import testing
+			{
+				ResourceName:                         "dt_device_labels.test",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportStateIdFunc: func(state *terraform.State) (string, error) {
+					return state.RootModule().Resources["dt_device_labels.test"].Primary.Attributes["name"], nil
+				},
+				// All the labels of the device, except for the system labels, are imported.
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					attributes := states[0].Attributes
+					if attributes["labels.%"] != "3" || attributes["labels.owner"] != "facilities" {
+						return fmt.Errorf("imported labels = %v, want room, floor and owner", attributes)
+					}
+					return nil
+				},
+				ImportStateVerifyIgnore: []string{"labels"},
+			},
+			{
+				// Import with an ID that is not a device name
+				ResourceName:  "dt_device_labels.test",
+				ImportState:   true,
+				ImportStateId: "emud2ltu8rp4ab4c73e09lcg",
+				ExpectError:   regexp.MustCompile("Invalid import ID"),
+			},
+			// Update testing, the removed label is removed from the device
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_labels/owned_updated.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_labels.test", "labels.%", "1"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.%", "4"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.room", "fridge"),
+					resource.TestCheckNoResourceAttr("data.dt_device.test", "labels.floor"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.owner", "facilities"),
+				),
+			},
+			// Authoritative labels remove the other labels, but not the system labels
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_labels/authoritative.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_labels.test", "authoritative", "true"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.%", "3"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.name", "labelled sensor"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.virtual-sensor", ""),
+					resource.TestCheckResourceAttr("data.dt_device.test", "labels.room", "fridge"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,401 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource               = &deviceTransferResource{}
+	_ resource.ResourceWithConfigure  = &deviceTransferResource{}
+	_ resource.ResourceWithModifyPlan = &deviceTransferResource{}
+)
+
+// NewDeviceTransferResource creates a new resource for transferring devices to a project.
+func NewDeviceTransferResource() resource.Resource {
+	return &deviceTransferResource{}
+}
+
+// deviceTransferResource moves existing devices into a project.
+type deviceTransferResource struct {
+	client *dt.Client
+}
+
+// Metadata returns the resource type name
+func (r *deviceTransferResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_device_transfer"
+}
+
+// Schema defines the schema for the resource.
+func (r *deviceTransferResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Transfers existing devices, such as sensors in the inventory project of an organization, into a project. The service account needs to be a project administrator of the projects the devices are moved between.",
+		Attributes: map[string]schema.Attribute{
+			"organization": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource name of the organization that the projects, and the projects the devices are transferred from, belong to. On the form `organizations/{organization_id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"project": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource name of the project to transfer the devices to. On the form `projects/{project_id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"devices": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "The resource names of the devices to transfer. On the form `projects/{project_id}/devices/{device_id}`, where the project is the one the device is in before it is transferred.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(
+						regexp.MustCompile(`^projects/[^/]+/devices/[^/]+$`),
+						"must be a device name on the form projects/{project_id}/devices/{device_id}",
+					)),
+				},
+			},
+			"return_project": schema.StringAttribute{
+				Optional:    true,
+				Description: "The resource name of the project to transfer the devices back to when they are removed from devices, or the resource is destroyed. On the form `projects/{project_id}`. The devices are left in project if not set.",
+			},
+		},
+	}
+}
+
+type deviceTransferResourceModel struct {
+	Organization  types.String `tfsdk:"organization"`
+	Project       types.String `tfsdk:"project"`
+	Devices       types.Set    `tfsdk:"devices"`
+	ReturnProject types.String `tfsdk:"return_project"`
+}
+
+// ModifyPlan checks that the projects, and the projects the added devices are
+// transferred from, exist in the organization, and warns about the devices
+// that are going to be transferred.
+func (r *deviceTransferResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the resource is destroyed, or before the
+	// provider is configured.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan, state deviceTransferResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.Organization.IsUnknown() {
+		return
+	}
+
+	for _, attribute := range []string{"project", "return_project"} {
+		var project types.String
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(attribute), &project)...)
+		if project.IsNull() || project.IsUnknown() {
+			continue
+		}
+		resp.Diagnostics.Append(r.checkProject(ctx, project.ValueString(), plan.Organization.ValueString(), path.Root(attribute))...)
+	}
+	if resp.Diagnostics.HasError() || plan.Devices.IsUnknown() {
+		return
+	}
+
+	planned, diags := expandStringSet(ctx, plan.Devices)
+	resp.Diagnostics.Append(diags...)
+	previous, diags := expandStringSet(ctx, state.Devices)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	added := missingFrom(planned, previous)
+
+	// The devices already in the state were checked when they were added.
+	checked := make(map[string]bool)
+	for _, device := range added {
+		deviceName, err := names.ParseDeviceName(device)
+		if err != nil {
+			continue
+		}
+		source := deviceName.ProjectName().String()
+		if checked[source] {
+			continue
+		}
+		checked[source] = true
+		resp.Diagnostics.Append(r.checkProject(ctx, source, plan.Organization.ValueString(), path.Root("devices"))...)
+	}
+	if resp.Diagnostics.HasError() || plan.Project.IsUnknown() {
+		return
+	}
+
+	if len(added) > 0 {
+		resp.Diagnostics.AddAttributeWarning(path.Root("devices"), "Devices will be transferred",
+			fmt.Sprintf("%d devices will be transferred to %s:\n  %s", len(added), plan.Project.ValueString(), strings.Join(added, "\n  ")))
+	}
+	if removed := missingFrom(previous, planned); len(removed) > 0 && !plan.ReturnProject.IsNull() && !plan.ReturnProject.IsUnknown() {
+		resp.Diagnostics.AddAttributeWarning(path.Root("devices"), "Devices will be transferred back",
+			fmt.Sprintf("%d devices will be transferred back to %s:\n  %s", len(removed), plan.ReturnProject.ValueString(), strings.Join(removed, "\n  ")))
+	}
+}
+
+// Create transfers the devices to the project.
+func (r *deviceTransferResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan deviceTransferResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	devices, diags := expandStringSet(ctx, plan.Devices)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.transfer(ctx, plan.Project.ValueString(), devices, path.Root("project"), false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read removes the devices that are no longer in the project from the state,
+// so that they are transferred again.
+func (r *deviceTransferResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state deviceTransferResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	devices, diags := expandStringSet(ctx, state.Devices)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	current, err := r.locateDevices(ctx, devices)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to read transferred devices",
+			attribute: path.Root("devices"),
+			role:      roleProjectUser,
+		}, err))
+		return
+	}
+
+	var transferred []string
+	for _, device := range devices {
+		deviceName, err := names.ParseDeviceName(current[device])
+		if err == nil && deviceName.ProjectName().String() == state.Project.ValueString() {
+			transferred = append(transferred, device)
+		}
+	}
+	// Keep the set empty rather than null when no devices are left.
+	state.Devices, diags = flattenStringSetToAttr(ctx, append([]string{}, transferred...))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Update transfers the added devices to the project, and the removed devices
+// back to the return project if it is set.
+func (r *deviceTransferResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state deviceTransferResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, diags := expandStringSet(ctx, plan.Devices)
+	resp.Diagnostics.Append(diags...)
+	previous, diags := expandStringSet(ctx, state.Devices)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.ReturnProject.IsNull() {
+		resp.Diagnostics.Append(r.transfer(ctx, plan.ReturnProject.ValueString(), missingFrom(previous, planned), path.Root("return_project"), true)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(r.transfer(ctx, plan.Project.ValueString(), missingFrom(planned, previous), path.Root("project"), false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete transfers the devices back to the return project if it is set, and
+// otherwise leaves them in the project.
+func (r *deviceTransferResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state deviceTransferResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || state.ReturnProject.IsNull() {
+		return
+	}
+
+	devices, diags := expandStringSet(ctx, state.Devices)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(r.transfer(ctx, state.ReturnProject.ValueString(), devices, path.Root("return_project"), true)...)
+}
+
+// checkProject checks that the project exists, and that it belongs to the
+// organization.
+func (r *deviceTransferResource) checkProject(ctx context.Context, project, organization string, attribute path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	p, err := r.client.GetProject(ctx, project, organization)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to read project",
+			attribute: attribute,
+			role:      roleProjectUser,
+		}, err))
+		return diags
+	}
+	if p.Organization != organization {
+		diags.AddAttributeError(attribute, "Project in another organization",
+			fmt.Sprintf("The project %s belongs to %s, not %s. Devices can only be transferred between projects in the organization.", project, p.Organization, organization))
+	}
+	return diags
+}
+
+// locateDevices returns the current names of the devices, keyed by the names
+// they were given as, since the project in the name of a device changes when
+// it is transferred. Devices that do not exist are left out.
+func (r *deviceTransferResource) locateDevices(ctx context.Context, devices []string) (map[string]string, error) {
+	current := make(map[string]string)
+	if len(devices) == 0 {
+		return current, nil
+	}
+
+	ids := make(map[string][]string)
+	filter := dt.ListDevicesFilter{}
+	for _, device := range devices {
+		deviceName, err := names.ParseDeviceName(device)
+		if err != nil {
+			return nil, fmt.Errorf("dt: failed to parse resource name: %w", err)
+		}
+		ids[deviceName.Device] = append(ids[deviceName.Device], device)
+		filter.DeviceIDs = append(filter.DeviceIDs, deviceName.Device)
+	}
+	for device, err := range r.client.ListDevices(ctx, filter) {
+		if err != nil {
+			return nil, err
+		}
+		deviceName, err := names.ParseDeviceName(device.Name)
+		if err != nil {
+			return nil, fmt.Errorf("dt: failed to parse resource name: %w", err)
+		}
+		for _, given := range ids[deviceName.Device] {
+			current[given] = device.Name
+		}
+	}
+	return current, nil
+}
+
+// transfer moves the devices to the project, unless they are already in it.
+// Devices that do not exist are an error, unless ignoreMissing is set.
+func (r *deviceTransferResource) transfer(ctx context.Context, project string, devices []string, attribute path.Path, ignoreMissing bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+	current, err := r.locateDevices(ctx, devices)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to read devices",
+			attribute: path.Root("devices"),
+			role:      roleProjectUser,
+		}, err))
+		return diags
+	}
+
+	var move []string
+	for _, device := range devices {
+		name, ok := current[device]
+		if !ok {
+			if !ignoreMissing {
+				diags.AddAttributeError(path.Root("devices"), "Device not found",
+					fmt.Sprintf("The device %s does not exist, or the service account does not have access to it.", device))
+			}
+			continue
+		}
+		if deviceName, _ := names.ParseDeviceName(name); deviceName.ProjectName().String() != project {
+			move = append(move, name)
+		}
+	}
+	if diags.HasError() || len(move) == 0 {
+		return diags
+	}
+	slices.Sort(move)
+
+	if err := r.client.TransferDevices(ctx, project, move); err != nil {
+		diags.Append(clientErrorDiagnostic(clientError{
+			summary:   "Failed to transfer devices",
+			attribute: attribute,
+			role:      roleProjectAdmin,
+		}, err))
+	}
+	return diags
+}
+
+// missingFrom returns the elements of a that are not in b.
+func missingFrom(a, b []string) []string {
+	var missing []string
+	for _, element := range a {
+		if !slices.Contains(b, element) {
+			missing = append(missing, element)
+		}
+	}
+	slices.Sort(missing)
+	return missing
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *deviceTransferResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*dt.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *dt.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
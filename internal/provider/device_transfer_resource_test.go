@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccDeviceTransferResource(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and read testing
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_transfer/one_device.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_transfer.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.site", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.site", "devices.0.name", "projects/d2m0j2rp4ab4c73e0b2g/devices/emud2m0jfbp4ab4c73e0b30g"),
+					resource.TestCheckResourceAttr("data.dt_devices.inventory", "devices.#", "1"),
+				),
+			},
+			// Add a device
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_transfer/two_devices.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_transfer.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_devices.site", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_devices.inventory", "devices.#", "0"),
+				),
+			},
+			// Remove the first device, which is transferred back to the return project
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/device_transfer/other_device.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_device_transfer.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.site", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.site", "devices.0.name", "projects/d2m0j2rp4ab4c73e0b2g/devices/emud2m0jijp4ab4c73e0b3g0"),
+					resource.TestCheckResourceAttr("data.dt_devices.inventory", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.inventory", "devices.0.name", "projects/d2m0ivjp4ab4c73e0b1g/devices/emud2m0jfbp4ab4c73e0b30g"),
+				),
+			},
+			// A project that is not in the organization is rejected when planning
+			{
+				Config: providerConfig + `resource "dt_device_transfer" "test" {
+					organization   = "organizations/cvinmt9aq9sc738g6eog"
+					project        = "projects/d2m0j2rp4ab4c73e0b2g"
+					return_project = "projects/notinorganization"
+					devices        = ["projects/d2m0ivjp4ab4c73e0b1g/devices/emud2m0jijp4ab4c73e0b3g0"]
+				}`,
+				ExpectError: regexp.MustCompile("Failed to read project"),
+			},
+			// A device in a project that is not in the organization is rejected when planning
+			{
+				Config: providerConfig + `resource "dt_device_transfer" "test" {
+					organization   = "organizations/cvinmt9aq9sc738g6eog"
+					project        = "projects/d2m0j2rp4ab4c73e0b2g"
+					return_project = "projects/d2m0ivjp4ab4c73e0b1g"
+					devices        = ["projects/notinorganization/devices/emud2m0jijp4ab4c73e0b3g0"]
+				}`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Failed to read project"),
+			},
+		},
+	})
+}
@@ -0,0 +1,259 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &devicesDataSource{}
+	_ datasource.DataSourceWithConfigure = &devicesDataSource{}
+)
+
+func NewDevicesDataSource() datasource.DataSource {
+	return &devicesDataSource{}
+}
+
+type devicesDataSource struct {
+	client dt.Client
+}
+
+// Metadata returns the data source type name.
+func (d *devicesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_devices"
+}
+
+// Schema defines the schema for the data source.
+func (d *devicesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the devices that match all of the filters. Without filters, it lists every device the service account has access to.",
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Optional:    true,
+				Description: "The resource name of the project to list the devices of. On the form `projects/{project_id}`. Lists the devices of every project the service account has access to if not set.",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("organization")),
+				},
+			},
+			"organization": schema.StringAttribute{
+				Optional:    true,
+				Description: "The resource name of the organization to list the devices of, across all its projects. On the form `organizations/{organization_id}`.",
+			},
+			"label_filters": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only list the devices that have all of these labels. A label with an empty value matches devices that have the label, with any value.",
+			},
+			"device_types": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only list the devices of these types, such as `temperature` or `ccon`.",
+			},
+			"product_numbers": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only list the devices with these product numbers.",
+			},
+			"devices": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "The devices that match the filters, sorted by resource name.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed:    true,
+							Description: "The resource name of the device. On the form `projects/{project_id}/devices/{device_id}`.",
+						},
+						"device_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The resource ID of the device.",
+						},
+						"project_id": schema.StringAttribute{
+							Computed:    true,
+							Description: "The resource ID of the project.",
+						},
+						"type": schema.StringAttribute{
+							Computed:    true,
+							Description: "The type of the device.",
+						},
+						"labels": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "The labels of the device.",
+						},
+						"product_number": schema.StringAttribute{
+							Computed:    true,
+							Description: "The product number of the device.",
+						},
+						"connection_status": schema.StringAttribute{
+							Computed:    true,
+							Description: "How a cloud connector is connected to the cloud, `ETHERNET`, `CELLULAR` or `OFFLINE`. Null for sensors.",
+						},
+						"signal_strength": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The signal strength, in percent, of the last event of a sensor. Null for cloud connectors, and sensors that have not sent any events.",
+						},
+						"battery_percentage": schema.Int64Attribute{
+							Computed:    true,
+							Description: "The remaining battery of a sensor, in percent. Null for cloud connectors, and sensors that have not reported it.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+type devicesDataSourceModel struct {
+	Project        types.String       `tfsdk:"project"`
+	Organization   types.String       `tfsdk:"organization"`
+	LabelFilters   types.Map          `tfsdk:"label_filters"`
+	DeviceTypes    types.Set          `tfsdk:"device_types"`
+	ProductNumbers types.Set          `tfsdk:"product_numbers"`
+	Devices        []devicesItemModel `tfsdk:"devices"`
+}
+
+type devicesItemModel struct {
+	Name              types.String `tfsdk:"name"`
+	DeviceID          types.String `tfsdk:"device_id"`
+	ProjectID         types.String `tfsdk:"project_id"`
+	Type              types.String `tfsdk:"type"`
+	Labels            types.Map    `tfsdk:"labels"`
+	ProductNumber     types.String `tfsdk:"product_number"`
+	ConnectionStatus  types.String `tfsdk:"connection_status"`
+	SignalStrength    types.Int64  `tfsdk:"signal_strength"`
+	BatteryPercentage types.Int64  `tfsdk:"battery_percentage"`
+}
+
+func (d *devicesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	// retrieve data source configuration
+	var config devicesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := dt.ListDevicesFilter{Project: config.Project.ValueString()}
+	resp.Diagnostics.Append(config.LabelFilters.ElementsAs(ctx, &filter.LabelFilters, false)...)
+	resp.Diagnostics.Append(config.DeviceTypes.ElementsAs(ctx, &filter.DeviceTypes, false)...)
+	resp.Diagnostics.Append(config.ProductNumbers.ElementsAs(ctx, &filter.ProductNumbers, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API can only list the devices of one project or of all projects,
+	// so the devices of an organization are listed project by project.
+	projects := []string{filter.Project}
+	attribute := path.Root("project")
+	if organization := config.Organization.ValueString(); organization != "" {
+		projects = nil
+		attribute = path.Root("organization")
+		for project, err := range d.client.ListProjects(ctx, organization) {
+			if err != nil {
+				resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+					summary:   "failed to list projects",
+					attribute: attribute,
+					role:      roleProjectUser,
+				}, err))
+				return
+			}
+			projects = append(projects, project.Name)
+		}
+	}
+
+	config.Devices = []devicesItemModel{}
+	for _, project := range projects {
+		filter.Project = project
+		for device, err := range d.client.ListDevices(ctx, filter) {
+			if err != nil {
+				resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+					summary:   "failed to list devices",
+					attribute: attribute,
+					role:      roleProjectUser,
+				}, err))
+				return
+			}
+			item, diags := newDevicesItem(ctx, device)
+			resp.Diagnostics.Append(diags...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			config.Devices = append(config.Devices, item)
+		}
+	}
+	// The API lists devices in no particular order, so sort them to keep
+	// references to them stable between reads.
+	slices.SortFunc(config.Devices, func(a, b devicesItemModel) int {
+		return strings.Compare(a.Name.ValueString(), b.Name.ValueString())
+	})
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &config)...)
+}
+
+// newDevicesItem returns the attributes of a device in the devices attribute.
+func newDevicesItem(ctx context.Context, device dt.Device) (devicesItemModel, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	deviceName, err := names.ParseDeviceName(device.Name)
+	if err != nil {
+		diags.AddError("failed to get device ID and project ID", err.Error())
+		return devicesItemModel{}, diags
+	}
+	labels, d := types.MapValueFrom(ctx, types.StringType, device.Labels)
+	diags.Append(d...)
+	item := devicesItemModel{
+		Name:              types.StringValue(device.Name),
+		DeviceID:          types.StringValue(deviceName.Device),
+		ProjectID:         types.StringValue(deviceName.Project),
+		Type:              types.StringValue(device.Type),
+		Labels:            labels,
+		ProductNumber:     types.StringValue(device.ProductNumber),
+		ConnectionStatus:  types.StringNull(),
+		SignalStrength:    types.Int64Null(),
+		BatteryPercentage: types.Int64Null(),
+	}
+	if reported := device.Reported; reported != nil {
+		if reported.ConnectionStatus != nil {
+			item.ConnectionStatus = types.StringValue(reported.ConnectionStatus.Connection)
+		}
+		if reported.NetworkStatus != nil {
+			item.SignalStrength = types.Int64Value(int64(reported.NetworkStatus.SignalStrength))
+		}
+		if reported.BatteryStatus != nil {
+			item.BatteryPercentage = types.Int64Value(int64(reported.BatteryStatus.Percentage))
+		}
+	}
+	return item, diags
+}
+
+func (d *devicesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*dt.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"invalid provider data",
+			fmt.Sprintf("Expected *dt.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = *client
+}
@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSafeDevicesDataSource(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Devices of a project, by label and type
+			{
+				Config: providerConfig + `data "dt_devices" "test" {
+					project       = "projects/cvinutal2ugc73b866v0"
+					device_types  = ["temperature"]
+					label_filters = { name = "manual temperature sensor", virtual-sensor = "" }
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.name", "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.device_id", "emucvio050h6oic7398hljg"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.project_id", "cvinutal2ugc73b866v0"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.type", "temperature"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.labels.name", "manual temperature sensor"),
+					resource.TestCheckNoResourceAttr("data.dt_devices.test", "devices.0.connection_status"),
+				),
+			},
+			// Devices of an organization
+			{
+				Config: providerConfig + `data "dt_devices" "test" {
+					organization  = "organizations/cvinmt9aq9sc738g6eog"
+					device_types  = ["ccon"]
+					label_filters = { name = "signal tower" }
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.name", "projects/d0919uq3tjjs739bf18g/devices/emud091aassh1nc738nel0g"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.type", "ccon"),
+				),
+			},
+			// Devices of all projects, sorted by resource name
+			{
+				Config: providerConfig + `data "dt_devices" "test" {
+					label_filters = { order-test = "" }
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.name", "projects/d2n0a0bp4ab4c73e0d00/devices/emuz2n0a2bp4ab4c73e0d10"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.1.name", "projects/d2n0a1bp4ab4c73e0d0g/devices/emua2n0a3bp4ab4c73e0d1g"),
+				),
+			},
+			// Devices of an organization, listed project by project
+			{
+				Config: providerConfig + `data "dt_devices" "test" {
+					organization  = "organizations/cvinmt9aq9sc738g6eog"
+					label_filters = { order-test = "" }
+				}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.0.project_id", "d2n0a0bp4ab4c73e0d00"),
+					resource.TestCheckResourceAttr("data.dt_devices.test", "devices.1.project_id", "d2n0a1bp4ab4c73e0d0g"),
+				),
+			},
+			// No matching devices
+			{
+				Config: providerConfig + `data "dt_devices" "test" {
+					project       = "projects/cvinutal2ugc73b866v0"
+					label_filters = { name = "no such device" }
+				}`,
+				Check: resource.TestCheckResourceAttr("data.dt_devices.test", "devices.#", "0"),
+			},
+		},
+	})
+}
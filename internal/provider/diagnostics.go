@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// Roles required by the resources, used to explain permission denied errors.
+const (
+	roleProjectUser       = "Project User (roles/project.user)"
+	roleProjectDeveloper  = "Project Developer (roles/project.developer)"
+	roleProjectAdmin      = "Project Admin (roles/project.admin)"
+	roleOrganizationAdmin = "Organization Admin (roles/organization.admin)"
+)
+
+// clientError describes an error returned by the dt client, for turning it
+// into a diagnostic.
+type clientError struct {
+	// summary describes the operation that failed.
+	summary string
+	// attribute is the attribute that identifies the resource the request was
+	// for, such as the project a resource is created in. It is empty when the
+	// error is not caused by a single attribute.
+	attribute path.Path
+	// role is the role the service account needs for the operation.
+	role string
+}
+
+// clientErrorDiagnostic returns a diagnostic for an error returned by the dt
+// client. The detail explains the API error and how to resolve it, and the
+// diagnostic is attached to the attribute of e when there is one.
+func clientErrorDiagnostic(e clientError, err error) diag.Diagnostic {
+	detail := err.Error()
+	if hint := clientErrorHint(e, err); hint != "" {
+		detail += "\n\n" + hint
+	}
+
+	var httpErr *dt.HTTPError
+	if errors.As(err, &httpErr) {
+		for _, d := range httpErr.Details {
+			detail += "\n\nDetails: " + string(d)
+		}
+		if httpErr.Help != "" {
+			detail += "\n\nSee " + httpErr.Help + " for more information."
+		}
+	}
+
+	if len(e.attribute.Steps()) == 0 {
+		return diag.NewErrorDiagnostic(e.summary, detail)
+	}
+	return diag.NewAttributeErrorDiagnostic(e.attribute, e.summary, detail)
+}
+
+// clientErrorHint returns a remediation hint for the kind of the error, or an
+// empty string if there is none.
+func clientErrorHint(e clientError, err error) string {
+	switch {
+	case errors.Is(err, dt.ErrUnauthenticated):
+		return "Check that key_id, key_secret and email, or the DT_API_KEY_ID, DT_API_KEY_SECRET and DT_OIDC_EMAIL " +
+			"environment variables, belong to an active service account key."
+	case errors.Is(err, dt.ErrPermissionDenied):
+		if e.role == "" {
+			return "The service account does not have access to the resource."
+		}
+		return fmt.Sprintf("The service account needs the %s role or higher for this operation. "+
+			"Grant the role to the service account in DT Studio.", e.role)
+	case errors.Is(err, dt.ErrNotFound):
+		if len(e.attribute.Steps()) == 0 {
+			return "The resource might have been deleted outside of Terraform."
+		}
+		return fmt.Sprintf("Check that %s refers to an existing resource, and that the service account has access to it.", attributeName(e.attribute))
+	case errors.Is(err, dt.ErrAlreadyExists):
+		return "The resource already exists. Import it into the Terraform state with `terraform import` instead of creating it."
+	case errors.Is(err, dt.ErrInvalidArgument):
+		return "The API rejected the configuration. Check the attributes against the error message above."
+	}
+	return ""
+}
+
+// attributeName returns the attribute path in the dotted form used in the documentation, such as `location.latitude`.
+func attributeName(p path.Path) string {
+	var parts []string
+	for _, step := range p.Steps() {
+		if name, ok := step.(path.PathStepAttributeName); ok {
+			parts = append(parts, string(name))
+		}
+	}
+	return "`" + strings.Join(parts, ".") + "`"
+}
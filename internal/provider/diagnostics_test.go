@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+func TestClientErrorDiagnostic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		e          clientError
+		err        error
+		wantPath   bool
+		wantDetail []string
+	}{
+		{
+			name: "permission denied names the role",
+			e:    clientError{summary: "failed to create project", attribute: path.Root("organization"), role: roleOrganizationAdmin},
+			err: fmt.Errorf("dt: failed to create project: %w", &dt.HTTPError{
+				StatusCode: 403,
+				Message:    "forbidden",
+				Code:       7,
+				Help:       "https://example.com/errors#403",
+			}),
+			wantPath:   true,
+			wantDetail: []string{"forbidden", "forbidden (code 7)", roleOrganizationAdmin, "https://example.com/errors#403"},
+		},
+		{
+			name:       "not found names the attribute",
+			e:          clientError{summary: "failed to create data connector", attribute: path.Root("project")},
+			err:        &dt.HTTPError{StatusCode: 404, Message: "project not found"},
+			wantPath:   true,
+			wantDetail: []string{"project not found", "`project` refers to an existing resource"},
+		},
+		{
+			name:       "unauthenticated points at the credentials",
+			e:          clientError{summary: "failed to get project"},
+			err:        fmt.Errorf("dt: failed to get OIDC token: %w", dt.ErrUnauthenticated),
+			wantDetail: []string{"DT_API_KEY_ID"},
+		},
+		{
+			name:       "other errors are passed through",
+			e:          clientError{summary: "failed to get project"},
+			err:        errors.New("connection refused"),
+			wantDetail: []string{"connection refused"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d := clientErrorDiagnostic(tt.e, tt.err)
+			if d.Severity() != diag.SeverityError {
+				t.Errorf("Severity() = %v, want error", d.Severity())
+			}
+			if d.Summary() != tt.e.summary {
+				t.Errorf("Summary() = %q, want %q", d.Summary(), tt.e.summary)
+			}
+			_, hasPath := d.(diag.DiagnosticWithPath)
+			if hasPath != tt.wantPath {
+				t.Errorf("diagnostic has path = %t, want %t", hasPath, tt.wantPath)
+			}
+			for _, want := range tt.wantDetail {
+				if !strings.Contains(d.Detail(), want) {
+					t.Errorf("Detail() = %q, want it to contain %q", d.Detail(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,569 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces
+var (
+	_ resource.Resource                     = &emulatorEventResource{}
+	_ resource.ResourceWithConfigure        = &emulatorEventResource{}
+	_ resource.ResourceWithConfigValidators = &emulatorEventResource{}
+	_ resource.ResourceWithModifyPlan       = &emulatorEventResource{}
+)
+
+// emulatorEventTypes are the attributes of the event types that can be
+// published, in the order they are listed in the schema.
+var emulatorEventTypes = []string{
+	"touch",
+	"temperature",
+	"humidity",
+	"co2",
+	"pressure",
+	"contact",
+	"motion",
+	"object_present",
+	"water_present",
+	"desk_occupancy",
+	"touch_count",
+	"object_present_count",
+	"network_status",
+	"battery_status",
+	"connection_status",
+}
+
+// emulatorSensorTypes are the emulator types of sensors, which are all but
+// cloud connectors.
+var emulatorSensorTypes = slices.DeleteFunc(slices.Clone(validEmulatorTypes), func(emulatorType string) bool {
+	return emulatorType == cloudConnectorType
+})
+
+// emulatorEventEmulatorTypes are the types of the emulators that can publish
+// each event type.
+var emulatorEventEmulatorTypes = map[string][]string{
+	"touch":                emulatorSensorTypes,
+	"temperature":          {"temperature"},
+	"humidity":             {"humidity", "co2"},
+	"co2":                  {"co2"},
+	"pressure":             {"co2"},
+	"contact":              {"contact"},
+	"motion":               {"motion"},
+	"object_present":       {"proximity"},
+	"water_present":        {"waterDetector"},
+	"desk_occupancy":       {"deskOccupancy"},
+	"touch_count":          {"touchCounter"},
+	"object_present_count": {"proximityCounter"},
+	"network_status":       emulatorSensorTypes,
+	"battery_status":       emulatorSensorTypes,
+	"connection_status":    {cloudConnectorType},
+}
+
+// NewEmulatorEventResource creates a new resource for publishing events from emulators.
+func NewEmulatorEventResource() resource.Resource {
+	return &emulatorEventResource{}
+}
+
+// emulatorEventResource publishes an event from an emulated device.
+type emulatorEventResource struct {
+	client *dt.Client
+}
+
+// Metadata returns the resource type name
+func (r *emulatorEventResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_emulator_event"
+}
+
+// eventAttribute returns an optional event type attribute. Changing it
+// publishes the event again.
+func eventAttribute(description string, attributes map[string]schema.Attribute) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:    true,
+		Description: description,
+		Attributes:  attributes,
+		PlanModifiers: []planmodifier.Object{
+			objectplanmodifier.RequiresReplace(),
+		},
+	}
+}
+
+// eventStateAttribute returns an event type attribute with a single state.
+func eventStateAttribute(description string, states ...string) schema.SingleNestedAttribute {
+	return eventAttribute(description, map[string]schema.Attribute{
+		"state": schema.StringAttribute{
+			Required:    true,
+			Description: "The state to publish, one of " + backtickedList(states) + ".",
+			Validators: []validator.String{
+				stringvalidator.OneOf(states...),
+			},
+		},
+	})
+}
+
+// eventTotalAttribute returns an event type attribute of a counter.
+func eventTotalAttribute(description string) schema.SingleNestedAttribute {
+	return eventAttribute(description, map[string]schema.Attribute{
+		"total": schema.Int64Attribute{
+			Required:    true,
+			Description: "The total count to publish.",
+			Validators: []validator.Int64{
+				int64validator.AtLeast(0),
+			},
+		},
+	})
+}
+
+// backtickedList formats values as a list of code spans, such as `a`, `b` or `c`.
+func backtickedList(values []string) string {
+	list := ""
+	for i, value := range values {
+		switch {
+		case i == 0:
+		case i == len(values)-1:
+			list += " or "
+		default:
+			list += ", "
+		}
+		list += "`" + value + "`"
+	}
+	return list
+}
+
+// Schema defines the schema for the resource.
+func (r *emulatorEventResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Publishes an event from an emulated device, as if it was sent by a physical device. The event is published when the resource is created, and again when any of its attributes, such as triggers, change. Exactly one event type must be set, and it must match the type of the emulator.",
+		Attributes: map[string]schema.Attribute{
+			"emulator": schema.StringAttribute{
+				Required:    true,
+				Description: "The resource name of the emulator to publish the event from. On the form `projects/{project_id}/devices/{device_id}`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Arbitrary values that publish the event again when they change.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"event_type": schema.StringAttribute{
+				Computed:    true,
+				Description: "The type of the published event, such as `temperature` or `connectionStatus`.",
+			},
+			"touch": eventAttribute("A touch event, for sensors. Set it to `{}`.", map[string]schema.Attribute{}),
+			"temperature": eventAttribute("A temperature event, for temperature sensors.", map[string]schema.Attribute{
+				"value": schema.Float64Attribute{
+					Required:    true,
+					Description: "The temperature in degrees Celsius.",
+				},
+			}),
+			"humidity": eventAttribute("A humidity event, for humidity and CO2 sensors.", map[string]schema.Attribute{
+				"temperature": schema.Float64Attribute{
+					Required:    true,
+					Description: "The temperature in degrees Celsius.",
+				},
+				"relative_humidity": schema.Float64Attribute{
+					Required:    true,
+					Description: "The relative humidity in percent.",
+					Validators: []validator.Float64{
+						float64validator.Between(0, 100),
+					},
+				},
+			}),
+			"co2": eventAttribute("A CO2 event, for CO2 sensors.", map[string]schema.Attribute{
+				"ppm": schema.Int64Attribute{
+					Required:    true,
+					Description: "The CO2 concentration in parts per million.",
+					Validators: []validator.Int64{
+						int64validator.AtLeast(0),
+					},
+				},
+			}),
+			"pressure": eventAttribute("A barometric pressure event, for CO2 sensors.", map[string]schema.Attribute{
+				"pascal": schema.Float64Attribute{
+					Required:    true,
+					Description: "The pressure in pascal.",
+				},
+			}),
+			"contact":              eventStateAttribute("A contact event, for contact sensors.", "OPEN", "CLOSED"),
+			"motion":               eventStateAttribute("A motion event, for motion sensors.", "MOTION_DETECTED", "NO_MOTION_DETECTED"),
+			"object_present":       eventStateAttribute("An object present event, for proximity sensors.", "PRESENT", "NOT_PRESENT"),
+			"water_present":        eventStateAttribute("A water present event, for water detectors.", "PRESENT", "NOT_PRESENT"),
+			"desk_occupancy":       eventStateAttribute("A desk occupancy event, for desk occupancy sensors.", "OCCUPIED", "NOT_OCCUPIED"),
+			"touch_count":          eventTotalAttribute("A touch count event, for touch counters."),
+			"object_present_count": eventTotalAttribute("An object present count event, for proximity counters."),
+			"network_status": eventAttribute("A network status event, for sensors.", map[string]schema.Attribute{
+				"signal_strength": schema.Int64Attribute{
+					Required:    true,
+					Description: "The signal strength in percent.",
+					Validators: []validator.Int64{
+						int64validator.Between(0, 100),
+					},
+				},
+				"rssi": schema.Int64Attribute{
+					Required:    true,
+					Description: "The received signal strength indicator, in dBm.",
+				},
+				"cloud_connectors": schema.ListNestedAttribute{
+					Optional:    true,
+					Description: "The cloud connectors that received the event. None if not set.",
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"id": schema.StringAttribute{
+								Required:    true,
+								Description: "The device ID of the cloud connector.",
+							},
+							"signal_strength": schema.Int64Attribute{
+								Required:    true,
+								Description: "The signal strength in percent.",
+								Validators: []validator.Int64{
+									int64validator.Between(0, 100),
+								},
+							},
+							"rssi": schema.Int64Attribute{
+								Required:    true,
+								Description: "The received signal strength indicator, in dBm.",
+							},
+						},
+					},
+				},
+				"transmission_mode": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "The transmission mode of the sensor, `LOW_POWER_STANDARD_MODE` or `HIGH_POWER_BOOST_MODE`. Defaults to `LOW_POWER_STANDARD_MODE`.",
+					Validators: []validator.String{
+						stringvalidator.OneOf("LOW_POWER_STANDARD_MODE", "HIGH_POWER_BOOST_MODE"),
+					},
+					Default: stringdefault.StaticString("LOW_POWER_STANDARD_MODE"),
+				},
+			}),
+			"battery_status": eventAttribute("A battery status event, for sensors.", map[string]schema.Attribute{
+				"percentage": schema.Int64Attribute{
+					Required:    true,
+					Description: "The remaining battery in percent.",
+					Validators: []validator.Int64{
+						int64validator.Between(0, 100),
+					},
+				},
+			}),
+			"connection_status": eventAttribute("A connection status event, for cloud connectors.", map[string]schema.Attribute{
+				"connection": schema.StringAttribute{
+					Required:    true,
+					Description: "How the cloud connector is connected to the cloud, `ETHERNET`, `CELLULAR` or `OFFLINE`.",
+					Validators: []validator.String{
+						stringvalidator.OneOf("ETHERNET", "CELLULAR", "OFFLINE"),
+					},
+				},
+				"available": schema.ListAttribute{
+					Optional:    true,
+					ElementType: types.StringType,
+					Description: "The connections the cloud connector has available, `ETHERNET` and `CELLULAR`. Defaults to the connection, or none when it is `OFFLINE`.",
+					Validators: []validator.List{
+						listvalidator.ValueStringsAre(stringvalidator.OneOf("ETHERNET", "CELLULAR")),
+					},
+				},
+			}),
+		},
+	}
+}
+
+// ConfigValidators checks that exactly one event type is set.
+func (r *emulatorEventResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	expressions := make([]path.Expression, 0, len(emulatorEventTypes))
+	for _, eventType := range emulatorEventTypes {
+		expressions = append(expressions, path.MatchRoot(eventType))
+	}
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(expressions...),
+	}
+}
+
+type emulatorEventResourceModel struct {
+	Emulator           types.String                `tfsdk:"emulator"`
+	Triggers           types.Map                   `tfsdk:"triggers"`
+	EventType          types.String                `tfsdk:"event_type"`
+	Touch              *struct{}                   `tfsdk:"touch"`
+	Temperature        *temperatureEventModel      `tfsdk:"temperature"`
+	Humidity           *humidityEventModel         `tfsdk:"humidity"`
+	CO2                *co2EventModel              `tfsdk:"co2"`
+	Pressure           *pressureEventModel         `tfsdk:"pressure"`
+	Contact            *stateEventModel            `tfsdk:"contact"`
+	Motion             *stateEventModel            `tfsdk:"motion"`
+	ObjectPresent      *stateEventModel            `tfsdk:"object_present"`
+	WaterPresent       *stateEventModel            `tfsdk:"water_present"`
+	DeskOccupancy      *stateEventModel            `tfsdk:"desk_occupancy"`
+	TouchCount         *totalEventModel            `tfsdk:"touch_count"`
+	ObjectPresentCount *totalEventModel            `tfsdk:"object_present_count"`
+	NetworkStatus      *networkStatusEventModel    `tfsdk:"network_status"`
+	BatteryStatus      *batteryStatusEventModel    `tfsdk:"battery_status"`
+	ConnectionStatus   *connectionStatusEventModel `tfsdk:"connection_status"`
+}
+
+type temperatureEventModel struct {
+	Value types.Float64 `tfsdk:"value"`
+}
+
+type humidityEventModel struct {
+	Temperature      types.Float64 `tfsdk:"temperature"`
+	RelativeHumidity types.Float64 `tfsdk:"relative_humidity"`
+}
+
+type co2EventModel struct {
+	PPM types.Int64 `tfsdk:"ppm"`
+}
+
+type pressureEventModel struct {
+	Pascal types.Float64 `tfsdk:"pascal"`
+}
+
+type stateEventModel struct {
+	State types.String `tfsdk:"state"`
+}
+
+type totalEventModel struct {
+	Total types.Int64 `tfsdk:"total"`
+}
+
+type networkStatusEventModel struct {
+	SignalStrength   types.Int64                        `tfsdk:"signal_strength"`
+	RSSI             types.Int64                        `tfsdk:"rssi"`
+	CloudConnectors  []networkStatusConnectorEventModel `tfsdk:"cloud_connectors"`
+	TransmissionMode types.String                       `tfsdk:"transmission_mode"`
+}
+
+type networkStatusConnectorEventModel struct {
+	ID             types.String `tfsdk:"id"`
+	SignalStrength types.Int64  `tfsdk:"signal_strength"`
+	RSSI           types.Int64  `tfsdk:"rssi"`
+}
+
+type batteryStatusEventModel struct {
+	Percentage types.Int64 `tfsdk:"percentage"`
+}
+
+type connectionStatusEventModel struct {
+	Connection types.String   `tfsdk:"connection"`
+	Available  []types.String `tfsdk:"available"`
+}
+
+// toEvent returns the event of the model, as the block of the reported state
+// for its event type.
+func (m emulatorEventResourceModel) toEvent() dt.Reported {
+	var event dt.Reported
+	if m.Touch != nil {
+		event.Touch = &dt.Touch{}
+	}
+	if m.Temperature != nil {
+		event.Temperature = &dt.Temperature{Value: m.Temperature.Value.ValueFloat64()}
+	}
+	if m.Humidity != nil {
+		event.Humidity = &dt.Humidity{
+			Temperature:      m.Humidity.Temperature.ValueFloat64(),
+			RelativeHumidity: m.Humidity.RelativeHumidity.ValueFloat64(),
+		}
+	}
+	if m.CO2 != nil {
+		event.CO2 = &dt.CO2{PPM: int(m.CO2.PPM.ValueInt64())}
+	}
+	if m.Pressure != nil {
+		event.Pressure = &dt.Pressure{Pascal: m.Pressure.Pascal.ValueFloat64()}
+	}
+	if m.Contact != nil {
+		event.Contact = &dt.ContactState{State: m.Contact.State.ValueString()}
+	}
+	if m.Motion != nil {
+		event.Motion = &dt.Motion{State: m.Motion.State.ValueString()}
+	}
+	if m.ObjectPresent != nil {
+		event.ObjectPresent = &dt.ObjectPresent{State: m.ObjectPresent.State.ValueString()}
+	}
+	if m.WaterPresent != nil {
+		event.WaterPresent = &dt.WaterPresent{State: m.WaterPresent.State.ValueString()}
+	}
+	if m.DeskOccupancy != nil {
+		event.DeskOccupancy = &dt.DeskOccupancy{State: m.DeskOccupancy.State.ValueString()}
+	}
+	if m.TouchCount != nil {
+		event.TouchCount = &dt.TouchCount{Total: int(m.TouchCount.Total.ValueInt64())}
+	}
+	if m.ObjectPresentCount != nil {
+		event.ObjectPresentCount = &dt.ObjectPresentCount{Total: int(m.ObjectPresentCount.Total.ValueInt64())}
+	}
+	if m.NetworkStatus != nil {
+		event.NetworkStatus = &dt.NetworkStatus{
+			SignalStrength:   int(m.NetworkStatus.SignalStrength.ValueInt64()),
+			RSSI:             int(m.NetworkStatus.RSSI.ValueInt64()),
+			CloudConnectors:  []dt.NetworkStatusConnector{},
+			TransmissionMode: m.NetworkStatus.TransmissionMode.ValueString(),
+		}
+		for _, connector := range m.NetworkStatus.CloudConnectors {
+			event.NetworkStatus.CloudConnectors = append(event.NetworkStatus.CloudConnectors, dt.NetworkStatusConnector{
+				ID:             connector.ID.ValueString(),
+				SignalStrength: int(connector.SignalStrength.ValueInt64()),
+				RSSI:           int(connector.RSSI.ValueInt64()),
+			})
+		}
+	}
+	if m.BatteryStatus != nil {
+		event.BatteryStatus = &dt.BatteryStatus{Percentage: int(m.BatteryStatus.Percentage.ValueInt64())}
+	}
+	if m.ConnectionStatus != nil {
+		event.ConnectionStatus = newConnectionStatus(m.ConnectionStatus.Connection.ValueString())
+		if m.ConnectionStatus.Available != nil {
+			event.ConnectionStatus.Available = []string{}
+			for _, value := range m.ConnectionStatus.Available {
+				event.ConnectionStatus.Available = append(event.ConnectionStatus.Available, value.ValueString())
+			}
+		}
+	}
+	return event
+}
+
+// ModifyPlan checks that the event type can be published from the type of
+// the emulator, so that a mismatch fails when planning rather than when the
+// event is published.
+func (r *emulatorEventResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when the resource is destroyed, or before the
+	// provider is configured.
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var emulatorName types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("emulator"), &emulatorName)...)
+	// The name of an emulator that is created in the same apply is unknown.
+	if resp.Diagnostics.HasError() || emulatorName.IsUnknown() {
+		return
+	}
+
+	for _, eventType := range emulatorEventTypes {
+		var event types.Object
+		resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root(eventType), &event)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if event.IsNull() {
+			continue
+		}
+
+		emulator, err := r.client.GetEmulator(ctx, emulatorName.ValueString())
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+				summary:   "Error reading emulator",
+				attribute: path.Root("emulator"),
+				role:      roleProjectUser,
+			}, err))
+			return
+		}
+		if emulatorTypes := emulatorEventEmulatorTypes[eventType]; !slices.Contains(emulatorTypes, emulator.Type) {
+			resp.Diagnostics.AddAttributeError(path.Root(eventType), "Event type does not match the emulator",
+				fmt.Sprintf("A %s event can not be published from %s, which is a %s emulator. It can only be published from %s emulators.",
+					eventType, emulatorName.ValueString(), emulator.Type, strings.Join(emulatorTypes, ", ")))
+		}
+		return
+	}
+}
+
+// Create publishes the event.
+func (r *emulatorEventResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan emulatorEventResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	event := plan.toEvent()
+	if err := r.client.PublishEmulatorEvent(ctx, plan.Emulator.ValueString(), event); err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Error publishing emulator event",
+			attribute: path.Root("emulator"),
+			role:      roleProjectDeveloper,
+		}, err))
+		return
+	}
+	plan.EventType = types.StringValue(event.EventTypes()[0])
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Read removes the event from the state if the emulator has been deleted,
+// so that it is published again when the emulator is recreated.
+func (r *emulatorEventResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state emulatorEventResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, err := r.client.GetEmulator(ctx, state.Emulator.ValueString()); err != nil {
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Error reading emulator",
+			attribute: path.Root("emulator"),
+			role:      roleProjectUser,
+		}, err))
+	}
+}
+
+// Update is never called, since every attribute that can be configured
+// publishes the event again by replacing the resource.
+func (r *emulatorEventResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan emulatorEventResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete only removes the event from the state, since published events can
+// not be deleted.
+func (r *emulatorEventResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *emulatorEventResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*dt.Client)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *dt.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.client = client
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccEmulatorEventResource(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Publish an event when the resource is created
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator_event/temperature.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_emulator_event.test", "event_type", "temperature"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "temperature.value", "21.5"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "latest.event_type", "temperature"),
+				),
+			},
+			// Publish the event again when the triggers change
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator_event/temperature_republished.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device.test", "temperature.value", "22.5"),
+					resource.TestCheckResourceAttr("data.dt_device_events.test", "events.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEmulatorEventResourceConnectionStatus(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The available connections default to the connection
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator_event/ccon.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_emulator_event.test", "event_type", "connectionStatus"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.connection", "CELLULAR"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.available.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.available.0", "CELLULAR"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEmulatorEventResourceNetworkStatus(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// The cloud connectors are published, in the default transmission mode
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator_event/network_status.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_emulator_event.test", "event_type", "networkStatus"),
+					resource.TestCheckResourceAttr("dt_emulator_event.test", "network_status.transmission_mode", "LOW_POWER_STANDARD_MODE"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "network_status.transmission_mode", "LOW_POWER_STANDARD_MODE"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "network_status.cloud_connectors.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "network_status.cloud_connectors.0.id", "emulated-ccon"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "network_status.cloud_connectors.0.rssi", "-60"),
+				),
+			},
+			// Changing the transmission mode publishes the event again
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator_event/network_status_boost.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device.test", "network_status.transmission_mode", "HIGH_POWER_BOOST_MODE"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEmulatorEventResourceInvalid(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// No event type
+			{
+				Config: providerConfig + `resource "dt_emulator_event" "test" {
+					emulator = "projects/d0ito5m62hus73ae3lr0/devices/emud0ito5m62hus73ae3lr0"
+				}`,
+				ExpectError: regexp.MustCompile("Missing Attribute Configuration"),
+			},
+			// Two event types
+			{
+				Config: providerConfig + `resource "dt_emulator_event" "test" {
+					emulator = "projects/d0ito5m62hus73ae3lr0/devices/emud0ito5m62hus73ae3lr0"
+					touch    = {}
+					temperature = {
+						value = 20
+					}
+				}`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Combination"),
+			},
+			// Invalid state
+			{
+				Config: providerConfig + `resource "dt_emulator_event" "test" {
+					emulator = "projects/d0ito5m62hus73ae3lr0/devices/emud0ito5m62hus73ae3lr0"
+					contact = {
+						state = "AJAR"
+					}
+				}`,
+				ExpectError: regexp.MustCompile("Invalid Attribute Value Match"),
+			},
+			// An event type that the emulator can not publish
+			{
+				Config: providerConfig + `resource "dt_emulator_event" "test" {
+					emulator = "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"
+					connection_status = {
+						connection = "ETHERNET"
+					}
+				}`,
+				ExpectError: regexp.MustCompile("Event type does not match the emulator"),
+			},
+		},
+	})
+}
@@ -4,10 +4,15 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -23,9 +28,10 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource                = &emulatorResource{}
-	_ resource.ResourceWithConfigure   = &emulatorResource{}
-	_ resource.ResourceWithImportState = &emulatorResource{}
+	_ resource.Resource                   = &emulatorResource{}
+	_ resource.ResourceWithConfigure      = &emulatorResource{}
+	_ resource.ResourceWithImportState    = &emulatorResource{}
+	_ resource.ResourceWithValidateConfig = &emulatorResource{}
 )
 
 var (
@@ -45,6 +51,15 @@ var (
 	}
 )
 
+const (
+	// cloudConnectorType is the type of emulated cloud connectors.
+	cloudConnectorType = "ccon"
+	// emulatedSignalStrength and emulatedRSSI are how well emulated sensors
+	// reach the emulated cloud connectors that report them.
+	emulatedSignalStrength = 99
+	emulatedRSSI           = -50
+)
+
 // NewEmulatorResource is a helper function to simplify the provider implementation.
 func NewEmulatorResource() resource.Resource {
 	return &emulatorResource{}
@@ -60,8 +75,7 @@ func (r *emulatorResource) Metadata(_ context.Context, req resource.MetadataRequ
 }
 
 func (r *emulatorResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseDeviceName)
 }
 
 func (r *emulatorResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -109,17 +123,58 @@ func (r *emulatorResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Description: "A map of labels to assign to the emulator.",
 				Default:     mapdefault.StaticValue(labelDefault),
 			},
+			"connection_state": schema.StringAttribute{
+				Optional:    true,
+				Description: "How an emulated cloud connector is connected to the cloud, `ETHERNET`, `CELLULAR` or `OFFLINE`. A connection status event is published when it changes. Only for the `ccon` type.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("ETHERNET", "CELLULAR", "OFFLINE"),
+				},
+			},
+			"sensors": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "The resource names of the emulated sensors that an emulated cloud connector reports, on the form `projects/{project_id}/devices/{device_id}`. A network status event that lists the cloud connector is published for each sensor when it is added, and for every sensor when the cloud connector comes back online. A network status event without the cloud connector is published for each sensor when it is removed. Only for the `ccon` type.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.RegexMatches(
+						regexp.MustCompile(`^projects/[^/]+/devices/[^/]+$`),
+						"must be on the form projects/{project_id}/devices/{device_id}",
+					)),
+				},
+			},
 		},
 	}
 }
 
 type emulatorResourceModel struct {
-	Name         types.String `tfsdk:"name"`
-	DisplayName  types.String `tfsdk:"display_name"`
-	ProjectID    types.String `tfsdk:"project_id"`
-	Type         types.String `tfsdk:"type"`
-	SystemLabels types.Map    `tfsdk:"system_labels"`
-	Labels       types.Map    `tfsdk:"labels"`
+	Name            types.String `tfsdk:"name"`
+	DisplayName     types.String `tfsdk:"display_name"`
+	ProjectID       types.String `tfsdk:"project_id"`
+	Type            types.String `tfsdk:"type"`
+	SystemLabels    types.Map    `tfsdk:"system_labels"`
+	Labels          types.Map    `tfsdk:"labels"`
+	ConnectionState types.String `tfsdk:"connection_state"`
+	Sensors         types.Set    `tfsdk:"sensors"`
+}
+
+// ValidateConfig checks that only cloud connectors have a connection and sensors.
+func (r *emulatorResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config emulatorResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Type.IsUnknown() || config.Type.ValueString() == cloudConnectorType {
+		return
+	}
+	for attribute, value := range map[string]attr.Value{"connection_state": config.ConnectionState, "sensors": config.Sensors} {
+		if !value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attribute),
+				"Invalid Attribute Combination",
+				fmt.Sprintf("%s can only be set for emulators of type %s, got type %s.", attribute, cloudConnectorType, config.Type.ValueString()),
+			)
+		}
+	}
 }
 
 // Create creates the resource and sets the initial Terraform state.
@@ -141,7 +196,11 @@ func (r *emulatorResource) Create(ctx context.Context, req resource.CreateReques
 	// Create the emulator
 	created, err := r.client.CreateEmulator(ctx, plan.ProjectID.ValueString(), toBeCreated)
 	if err != nil {
-		resp.Diagnostics.AddError("Error creating emulator", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Error creating emulator",
+			attribute: path.Root("project_id"),
+			role:      roleProjectDeveloper,
+		}, err))
 		return
 	}
 
@@ -150,6 +209,16 @@ func (r *emulatorResource) Create(ctx context.Context, req resource.CreateReques
 	if diags.HasError() {
 		return
 	}
+	state.ConnectionState = plan.ConnectionState
+	state.Sensors = plan.Sensors
+
+	publishDiags := r.publishCloudConnector(ctx, created.Name, state, nil)
+	resp.Diagnostics.Append(publishDiags...)
+	if publishDiags.HasError() {
+		// Keep the emulator in the state, and publish again on the next apply.
+		state.ConnectionState = types.StringNull()
+		state.Sensors = types.SetNull(types.StringType)
+	}
 
 	// Set the Terraform state
 	diags = resp.State.Set(ctx, state)
@@ -172,15 +241,27 @@ func (r *emulatorResource) Read(ctx context.Context, req resource.ReadRequest, r
 	// Get the emulator
 	emulator, err := r.client.GetEmulator(ctx, state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Error reading emulator", err.Error())
+		// The emulator was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error reading emulator",
+			role:    roleProjectUser,
+		}, err))
 		return
 	}
 
+	connection, sensors := state.ConnectionState, state.Sensors
 	state, diags = emulatorToState(ctx, emulator)
 	resp.Diagnostics.Append(diags...)
 	if diags.HasError() {
 		return
 	}
+	// The connection and sensors are published events, so they are kept as they are.
+	state.ConnectionState = connection
+	state.Sensors = sensors
 
 	// Set the Terraform state
 	diags = resp.State.Set(ctx, state)
@@ -217,15 +298,29 @@ func (r *emulatorResource) Update(ctx context.Context, req resource.UpdateReques
 	// Update the emulator
 	updated, err := r.client.UpdateEmulator(ctx, toBeUpdated)
 	if err != nil {
-		resp.Diagnostics.AddError("Error updating emulator", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error updating emulator",
+			role:    roleProjectDeveloper,
+		}, err))
 		return
 	}
 
+	previous := state
 	state, diags = emulatorToState(ctx, updated)
 	resp.Diagnostics.Append(diags...)
 	if diags.HasError() {
 		return
 	}
+	state.ConnectionState = plan.ConnectionState
+	state.Sensors = plan.Sensors
+
+	publishDiags := r.publishCloudConnector(ctx, updated.Name, state, &previous)
+	resp.Diagnostics.Append(publishDiags...)
+	if publishDiags.HasError() {
+		// Keep the previous connection and sensors, so that they are published again on the next apply.
+		state.ConnectionState = previous.ConnectionState
+		state.Sensors = previous.Sensors
+	}
 
 	// Set the Terraform state
 	diags = resp.State.Set(ctx, state)
@@ -247,8 +342,15 @@ func (r *emulatorResource) Delete(ctx context.Context, req resource.DeleteReques
 
 	// Delete the emulator
 	err := r.client.DeleteEmulator(ctx, state.Name.ValueString())
+	// Nothing to do if it has already been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Error deleting emulator", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error deleting emulator",
+			role:    roleProjectDeveloper,
+		}, err))
 		return
 	}
 }
@@ -273,6 +375,97 @@ func (r *emulatorResource) Configure(ctx context.Context, req resource.Configure
 	r.client = client
 }
 
+// publishCloudConnector publishes the connection of an emulated cloud
+// connector if it has changed since the previous state, which is nil when the
+// emulator is created. It then publishes a network status event without the
+// cloud connector for each sensor that was removed, and unless the cloud
+// connector is offline, a network status event that lists it for each sensor
+// that it did not report before.
+func (r *emulatorResource) publishCloudConnector(ctx context.Context, name string, plan emulatorResourceModel, previous *emulatorResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	online := func(model emulatorResourceModel) bool {
+		return model.ConnectionState.ValueString() != "OFFLINE"
+	}
+
+	if !plan.ConnectionState.IsNull() && (previous == nil || !plan.ConnectionState.Equal(previous.ConnectionState)) {
+		event := dt.Reported{ConnectionStatus: newConnectionStatus(plan.ConnectionState.ValueString())}
+		if err := r.client.PublishEmulatorEvent(ctx, name, event); err != nil {
+			diags.Append(clientErrorDiagnostic(clientError{
+				summary:   "Error publishing connection status",
+				attribute: path.Root("connection_state"),
+				role:      roleProjectDeveloper,
+			}, err))
+			return diags
+		}
+	}
+
+	sensors, d := expandStringSet(ctx, plan.Sensors)
+	diags.Append(d...)
+	var previousSensors, reported []string
+	if previous != nil {
+		previousSensors, d = expandStringSet(ctx, previous.Sensors)
+		diags.Append(d...)
+		if online(*previous) {
+			reported = previousSensors
+		}
+	}
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, sensor := range missingFrom(previousSensors, sensors) {
+		// A sensor that has been deleted does not report anything.
+		if err := r.publishNetworkStatus(ctx, sensor, []dt.NetworkStatusConnector{}); err != nil && !dt.IsNotFound(err) {
+			diags.Append(clientErrorDiagnostic(clientError{
+				summary:   "Error publishing network status of " + sensor,
+				attribute: path.Root("sensors"),
+				role:      roleProjectDeveloper,
+			}, err))
+		}
+	}
+	if !online(plan) {
+		return diags
+	}
+
+	emulator := dt.Emulator{Name: name}
+	for _, sensor := range missingFrom(sensors, reported) {
+		connectors := []dt.NetworkStatusConnector{{
+			ID:             emulator.DeviceID(),
+			SignalStrength: emulatedSignalStrength,
+			RSSI:           emulatedRSSI,
+		}}
+		if err := r.publishNetworkStatus(ctx, sensor, connectors); err != nil {
+			diags.Append(clientErrorDiagnostic(clientError{
+				summary:   "Error publishing network status of " + sensor,
+				attribute: path.Root("sensors"),
+				role:      roleProjectDeveloper,
+			}, err))
+		}
+	}
+	return diags
+}
+
+// publishNetworkStatus publishes a network status event of an emulated
+// sensor, as received by the cloud connectors.
+func (r *emulatorResource) publishNetworkStatus(ctx context.Context, sensor string, connectors []dt.NetworkStatusConnector) error {
+	return r.client.PublishEmulatorEvent(ctx, sensor, dt.Reported{NetworkStatus: &dt.NetworkStatus{
+		SignalStrength:   emulatedSignalStrength,
+		RSSI:             emulatedRSSI,
+		CloudConnectors:  connectors,
+		TransmissionMode: "LOW_POWER_STANDARD_MODE",
+	}})
+}
+
+// newConnectionStatus returns the connection status of a cloud connector
+// that only has its current connection available.
+func newConnectionStatus(connection string) *dt.ConnectionStatus {
+	available := []string{}
+	if connection != "OFFLINE" {
+		available = append(available, connection)
+	}
+	return &dt.ConnectionStatus{Connection: connection, Available: available}
+}
+
 func stateToEmulator(ctx context.Context, state emulatorResourceModel) (dt.Emulator, diag.Diagnostics) {
 	var diags diag.Diagnostics
 
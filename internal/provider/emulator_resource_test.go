@@ -3,6 +3,7 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -67,3 +68,70 @@ func TestAccEmulatorResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccEmulatorResourceCloudConnector(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Publish the connection, and the network status of the sensor
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator/ccon_ethernet.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.connection", "ETHERNET"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_a", "events.#", "1"),
+					resource.TestCheckResourceAttrPair("data.dt_device.sensor_a", "network_status.cloud_connectors.0.id", "data.dt_device.test", "device_id"),
+				),
+			},
+			// Sensors added while offline are not reported
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator/ccon_offline.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.connection", "OFFLINE"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.available.#", "0"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_a", "events.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_b", "events.#", "0"),
+				),
+			},
+			// Every sensor is reported when the cloud connector comes back online
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator/ccon_cellular.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_emulator.test", "connection_state", "CELLULAR"),
+					resource.TestCheckResourceAttr("dt_emulator.test", "sensors.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_device.test", "connection_status.connection", "CELLULAR"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_a", "events.#", "2"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_b", "events.#", "1"),
+				),
+			},
+			// A removed sensor is no longer reported by the cloud connector
+			{
+				Config: providerConfig + readTestFile(t, "../../testdata/emulator/ccon_removed.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("dt_emulator.test", "sensors.#", "1"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_a", "events.#", "3"),
+					resource.TestCheckResourceAttr("data.dt_device.sensor_a", "network_status.cloud_connectors.#", "0"),
+					resource.TestCheckResourceAttr("data.dt_device_events.sensor_b", "events.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEmulatorResourceSensorsOnlyForCloudConnectors(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `resource "dt_emulator" "test" {
+					display_name = "Not a cloud connector"
+					project_id   = "d0ito5m62hus73ae3lr0"
+					type         = "touch"
+					connection_state = "ETHERNET"
+				}`,
+				ExpectError: regexp.MustCompile("connection_state can only be set for emulators of type ccon"),
+			},
+		},
+	})
+}
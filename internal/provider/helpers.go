@@ -9,6 +9,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
 )
@@ -77,3 +79,17 @@ var durationValidator = stringvalidator.RegexMatches(
 	regexp.MustCompile(`^(\d+)([s])$`),
 	"Duration must be in the format of <number><unit>, where unit is 's' (seconds).",
 )
+
+// importByName imports a resource by the resource name in its name attribute.
+// The import ID is parsed with parse first, so that a malformed ID fails with
+// an error that shows the expected format, instead of a failed read.
+func importByName[N any](ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse, parse func(string) (N, error)) {
+	if _, err := parse(req.ID); err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid import ID",
+			err.Error(),
+		)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -106,8 +107,7 @@ func (r *notificationRuleResource) Metadata(_ context.Context, req resource.Meta
 }
 
 func (r *notificationRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseRuleName)
 }
 
 // Schema defines the schema for the resource.
@@ -853,10 +853,19 @@ func (r *notificationRuleResource) Create(ctx context.Context, req resource.Crea
 	// Create the notification rule
 	created, err := r.client.CreateNotificationRule(ctx, parent, toBeCreated)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating notification rule",
-			fmt.Sprintf("Could not create notification rule: %s", err),
-		)
+		// Rules in an organization require more access than rules in a project.
+		parentAttribute, parentRole := path.Root("project_id"), roleProjectDeveloper
+		if !plan.ParentResourceName.IsNull() && !plan.ParentResourceName.IsUnknown() {
+			parentAttribute = path.Root("parent_resource_name")
+			if strings.HasPrefix(parent, "organizations/") {
+				parentRole = roleOrganizationAdmin
+			}
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Error creating notification rule",
+			attribute: parentAttribute,
+			role:      parentRole,
+		}, err))
 		return
 	}
 
@@ -896,10 +905,15 @@ func (r *notificationRuleResource) Read(ctx context.Context, req resource.ReadRe
 	// Read the notification rule
 	notificationRule, err := r.client.GetNotificationRule(ctx, state.Name.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error reading notification rule",
-			fmt.Sprintf("Could not read notification rule: %s", err),
-		)
+		// The notification rule was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error reading notification rule",
+			role:    roleProjectUser,
+		}, err))
 		return
 	}
 
@@ -938,11 +952,15 @@ func (r *notificationRuleResource) Delete(ctx context.Context, req resource.Dele
 
 	// Delete the notification rule
 	err := r.client.DeleteNotificationRule(ctx, state.Name.ValueString())
+	// Nothing to do if it has already been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting notification rule",
-			fmt.Sprintf("Could not delete notification rule: %s", err),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error deleting notification rule",
+			role:    roleProjectDeveloper,
+		}, err))
 		return
 	}
 }
@@ -967,10 +985,10 @@ func (r *notificationRuleResource) Update(ctx context.Context, req resource.Upda
 	// Update the notification rule
 	updated, err := r.client.UpdateNotificationRule(ctx, toBeUpdated)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating notification rule",
-			fmt.Sprintf("Could not update notification rule: %s", err),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error updating notification rule",
+			role:    roleProjectDeveloper,
+		}, err))
 		return
 	}
 
@@ -1039,7 +1057,7 @@ func notificationRuleToState(ctx context.Context, notificationRule dt.Notificati
 
 	// Convert the notification rule to the state model.
 	var state notificationRuleModel
-	parentType, parentID, _, err := dt.ParseRuleResourceName(notificationRule.Name)
+	ruleName, err := names.ParseRuleName(notificationRule.Name)
 	if err != nil {
 		diags.AddError(
 			"Error parsing notification rule name",
@@ -1051,9 +1069,9 @@ func notificationRuleToState(ctx context.Context, notificationRule dt.Notificati
 	state.Name = types.StringValue(notificationRule.Name)
 
 	if legacyProjectID {
-		state.ProjectID = types.StringValue(parentID)
+		state.ProjectID = types.StringValue(ruleName.Project)
 	} else {
-		state.ParentResourceName = types.StringValue(parentType + "/" + parentID)
+		state.ParentResourceName = types.StringValue(ruleName.Parent())
 	}
 
 	state.Enabled = types.BoolValue(notificationRule.Enabled)
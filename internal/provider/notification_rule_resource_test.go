@@ -5,6 +5,8 @@ package provider
 import (
 	"testing"
 
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
@@ -48,8 +50,9 @@ func TestAccNotificationRulesResourceExamples(t *testing.T) {
 
 func TestAccNotificationRuleResource(t *testing.T) {
 	t.Parallel()
+	factories := testAccProviderFactories(t)
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Create and read testing
 			{
@@ -113,7 +116,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 	})
 	resource.Test(t, resource.TestCase{
 		// Test case for the ccon offline trigger
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			{
 				Config: notificationRuleProviderConfig + readTestFile(t, "../../testdata/notification_rule/ccon_offline_trigger.tf"),
@@ -135,7 +138,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 	})
 	resource.Test(t, resource.TestCase{
 		// Test case for the sensor offline trigger
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			{
 				Config: notificationRuleProviderConfig + readTestFile(t, "../../testdata/notification_rule/sensor_offline_trigger.tf"),
@@ -157,7 +160,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Test case for the disabled rule
 			{
@@ -182,7 +185,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Test case for reminder notifications
 			{
@@ -206,7 +209,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Test case for "all" escalation types
 			{
@@ -283,7 +286,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// test case for signal tower
 			{
@@ -304,7 +307,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// test case for inverse schedule
 			{
@@ -339,7 +342,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Test case for the disabled rule
 			{
@@ -367,7 +370,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Test case for org level alerts
 			{
@@ -404,7 +407,7 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 	resource.Test(t, resource.TestCase{
-		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		ProtoV6ProviderFactories: factories,
 		Steps: []resource.TestStep{
 			// Test case for org level alerts
 			{
@@ -429,3 +432,21 @@ func TestAccNotificationRuleResource(t *testing.T) {
 		},
 	})
 }
+
+func TestAccNotificationRuleResourceDisappears(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Delete the notification rule outside of Terraform, the refresh should remove it from the state
+				Config: notificationRuleProviderConfig + readTestFile(t, "../../testdata/notification_rule/with_schedule.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("dt_notification_rule.test", "name"),
+					testAccCheckResourceDisappears("dt_notification_rule.test", (*dt.Client).DeleteNotificationRule),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
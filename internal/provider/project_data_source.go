@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -119,7 +120,11 @@ func (d *projectDataSource) Read(ctx context.Context, req datasource.ReadRequest
 
 	project, err := d.client.GetProject(ctx, config.Name.ValueString(), config.Organization.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("failed to get project", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "failed to get project",
+			attribute: path.Root("name"),
+			role:      roleProjectUser,
+		}, err))
 		return
 	}
 
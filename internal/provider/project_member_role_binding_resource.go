@@ -4,11 +4,11 @@ package provider
 
 import (
 	"context"
-	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -50,8 +50,7 @@ func (m *projectMemberRoleBindingsResource) Metadata(_ context.Context, req reso
 }
 
 func (r *projectMemberRoleBindingsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseRoleBindingName)
 }
 
 // Schema defines the schema for the resource.
@@ -160,10 +159,11 @@ func (m *projectMemberRoleBindingsResource) Create(ctx context.Context, req reso
 
 	members, err := m.client.BatchCreateMemberships(ctx, toBeCreated)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating project member",
-			"Could not create project member, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Error creating project member",
+			attribute: path.Root("projects"),
+			role:      roleOrganizationAdmin,
+		}, err))
 		return
 	}
 	state, d := membershipsToState(ctx, plan.Organization.ValueString(), members)
@@ -189,7 +189,7 @@ func (m *projectMemberRoleBindingsResource) Read(ctx context.Context, req resour
 		return
 	}
 
-	organizationID, roleID, memberID, err := decodeID(state.Name.ValueString())
+	bindingName, err := names.ParseRoleBindingName(state.Name.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error decoding project member ID",
@@ -197,16 +197,27 @@ func (m *projectMemberRoleBindingsResource) Read(ctx context.Context, req resour
 		)
 		return
 	}
-	organization := "organizations/" + organizationID
-	role := "roles/" + roleID
+	organization := bindingName.OrganizationName().String()
+	role := bindingName.RoleName()
+	memberID := bindingName.Member
 
 	// get the project members for the organization and member ID
 	members, err := m.client.ListProjectMemberships(ctx, organization, role, memberID)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error getting project member",
-			"Could not get project member, unexpected error: "+err.Error(),
-		)
+		// The role binding was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error getting project member",
+			role:    roleOrganizationAdmin,
+		}, err))
+		return
+	}
+	// The member no longer has the role in any project, so the binding was removed outside of Terraform.
+	if len(members) == 0 {
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
@@ -243,10 +254,11 @@ func (m *projectMemberRoleBindingsResource) Update(ctx context.Context, req reso
 
 	members, err := m.client.UpdateMemberships(ctx, memberships, plan.Role.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error updating project member",
-			"Could not update project member, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "Error updating project member",
+			attribute: path.Root("projects"),
+			role:      roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -282,10 +294,10 @@ func (m *projectMemberRoleBindingsResource) Delete(ctx context.Context, req reso
 
 	err := m.client.BatchDeleteMemberships(ctx, toBeDeleted)
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error deleting project member",
-			"Could not delete project member, unexpected error: "+err.Error(),
-		)
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "Error deleting project member",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 }
@@ -322,14 +334,15 @@ func stateToBatchDeleteProjectMembersRequest(ctx context.Context, plan membersRe
 		return dt.BatchDeleteProjectMembersRequest{}, diags
 	}
 	for _, project := range projects {
-		if project == "" {
+		projectName, err := names.ParseProjectName(project)
+		if err != nil {
 			diags.AddError(
 				"Error deleting project member",
-				"Project ID cannot be empty",
+				err.Error(),
 			)
 			return dt.BatchDeleteProjectMembersRequest{}, diags
 		}
-		membersToDelete = append(membersToDelete, fmt.Sprintf("%s/members/%s", project, memberID))
+		membersToDelete = append(membersToDelete, names.MemberName{Project: projectName.Project, Member: memberID}.String())
 	}
 	return dt.BatchDeleteProjectMembersRequest{
 		Names: membersToDelete,
@@ -371,15 +384,16 @@ func stateToMemberships(ctx context.Context, plan membersResourceModel) ([]dt.Me
 	}
 
 	for _, project := range projects {
-		if project == "" {
+		projectName, err := names.ParseProjectName(project)
+		if err != nil {
 			diags.AddError(
 				"Error getting project member",
-				"Project ID cannot be empty",
+				err.Error(),
 			)
 			return nil, diags
 		}
 		memberships = append(memberships, dt.Membership{
-			Name:        fmt.Sprintf("%s/members/%s", project, plan.MemberID.ValueString()),
+			Name:        names.MemberName{Project: projectName.Project, Member: plan.MemberID.ValueString()}.String(),
 			DisplayName: plan.MemberDisplayName.ValueString(),
 			Email:       plan.Email.ValueString(),
 			Roles:       []string{plan.Role.ValueString()},
@@ -392,7 +406,6 @@ func stateToMemberships(ctx context.Context, plan membersResourceModel) ([]dt.Me
 
 func membershipsToState(ctx context.Context, organization string, memberships []dt.Membership) (membersResourceModel, diag.Diagnostics) {
 	var diags diag.Diagnostics
-	var err error
 	var projects []string
 	var email string
 	var role string
@@ -421,16 +434,17 @@ func membershipsToState(ctx context.Context, organization string, memberships []
 			return membersResourceModel{}, diags
 		}
 
+		memberName, err := names.ParseMemberName(membership.Name)
+		if err != nil {
+			diags.AddError(
+				"Error parsing membership name",
+				"Could not parse membership name, unexpected error: "+err.Error(),
+			)
+			return membersResourceModel{}, diags
+		}
 		if memberID == "" {
-			_, memberID, err = dt.ParseResourceName(membership.Name)
-			if err != nil {
-				diags.AddError(
-					"Error parsing membership name",
-					"Could not parse membership name, unexpected error: "+err.Error(),
-				)
-				return membersResourceModel{}, diags
-			}
-		} else if !strings.Contains(membership.Name, memberID) {
+			memberID = memberName.Member
+		} else if memberName.Member != memberID {
 			diags.AddError(
 				"Error getting project member",
 				"Project memberships must have the same memberID",
@@ -456,15 +470,7 @@ func membershipsToState(ctx context.Context, organization string, memberships []
 			)
 			return membersResourceModel{}, diags
 		}
-		projectID, err := membership.ProjectID()
-		if err != nil {
-			diags.AddError(
-				"Error getting project ID",
-				"Could not get project ID, unexpected error: "+err.Error(),
-			)
-			return membersResourceModel{}, diags
-		}
-		projects = append(projects, "projects/"+projectID)
+		projects = append(projects, memberName.ProjectName().String())
 	}
 
 	projectsSet, d := flattenStringSetToAttr(ctx, projects)
@@ -473,11 +479,23 @@ func membershipsToState(ctx context.Context, organization string, memberships []
 		return membersResourceModel{}, diags
 	}
 
-	organizationID := strings.TrimPrefix(organization, "organizations/")
-	roleID := strings.TrimPrefix(role, "roles/")
+	organizationName, err := names.ParseOrganizationName(organization)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("organization"),
+			"Invalid organization",
+			err.Error(),
+		)
+		return membersResourceModel{}, diags
+	}
+	bindingName := names.RoleBindingName{
+		Organization: organizationName.Organization,
+		Role:         strings.TrimPrefix(role, "roles/"),
+		Member:       memberID,
+	}
 
 	return membersResourceModel{
-		Name:              types.StringValue(fmt.Sprintf("organizations/%s/roles/%s/members/%s", organizationID, roleID, memberID)),
+		Name:              types.StringValue(bindingName.String()),
 		MemberID:          types.StringValue(memberID),
 		MemberDisplayName: types.StringValue(displayName),
 		Projects:          projectsSet,
@@ -487,16 +505,3 @@ func membershipsToState(ctx context.Context, organization string, memberships []
 		AccountType:       types.StringValue(accountType),
 	}, diags
 }
-
-func decodeID(id string) (string, string, string, error) {
-	parts := strings.Split(id, "/")
-	if len(parts) != 6 {
-		return "", "", "", fmt.Errorf("invalid ID format: %s", id)
-	}
-
-	if parts[0] != "organizations" || parts[2] != "roles" || parts[4] != "members" {
-		return "", "", "", fmt.Errorf("invalid ID format: %s", id)
-	}
-
-	return parts[1], parts[3], parts[5], nil
-}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/names"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
@@ -44,8 +45,7 @@ func (r *projectResource) Metadata(_ context.Context, req resource.MetadataReque
 }
 
 func (r *projectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	importByName(ctx, req, resp, names.ParseProjectName)
 }
 
 // Schema defines the schema for the resource.
@@ -172,7 +172,11 @@ func (r *projectResource) Create(ctx context.Context, req resource.CreateRequest
 	// Create the project.
 	project, err := r.client.CreateProject(ctx, project)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to create project", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary:   "failed to create project",
+			attribute: path.Root("organization"),
+			role:      roleOrganizationAdmin,
+		}, err))
 		return
 	}
 
@@ -203,7 +207,15 @@ func (r *projectResource) Read(ctx context.Context, req resource.ReadRequest, re
 	// get the project from the API
 	project, err := r.client.GetProject(ctx, state.Name.ValueString(), state.Organization.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("failed to get project", err.Error())
+		// The project was deleted outside of Terraform, remove it from the state so that it is recreated.
+		if dt.IsNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to get project",
+			role:    roleProjectUser,
+		}, err))
 		return
 	}
 
@@ -241,7 +253,10 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	toBeUpdated := stateToUpdateProjectRequest(plan)
 	project, err := r.client.UpdateProject(ctx, toBeUpdated)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to update project", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to update project",
+			role:    roleProjectAdmin,
+		}, err))
 		return
 	}
 
@@ -253,7 +268,10 @@ func (r *projectResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 	project, err = r.client.SetProjectLabels(ctx, project, targetLabels)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to sync project labels", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to sync project labels",
+			role:    roleProjectAdmin,
+		}, err))
 		return
 	}
 
@@ -282,8 +300,15 @@ func (r *projectResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	// delete the project
 	err := r.client.DeleteProject(ctx, state.Name.ValueString())
+	// Nothing to do if it has already been deleted outside of Terraform.
+	if dt.IsNotFound(err) {
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("failed to delete project", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(clientError{
+			summary: "failed to delete project",
+			role:    roleOrganizationAdmin,
+		}, err))
 		return
 	}
 }
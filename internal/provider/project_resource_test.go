@@ -3,8 +3,11 @@
 package provider
 
 import (
+	"regexp"
 	"testing"
 
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
 )
@@ -57,6 +60,13 @@ func TestAccSafeProjectResource(t *testing.T) {
 					return state.RootModule().Resources["dt_project.test"].Primary.Attributes["name"], nil
 				},
 			},
+			{
+				// Import with an ID that is not a project name
+				ResourceName:  "dt_project.test",
+				ImportState:   true,
+				ImportStateId: "cvinutal2ugc73b866v0",
+				ExpectError:   regexp.MustCompile("Invalid import ID"),
+			},
 			{
 				// Update testing
 				Config: providerConfig + readTestFile(t, "../../testdata/project/with_updated_location.tf"),
@@ -144,3 +154,21 @@ resource "dt_project" "test_labels" {
 		},
 	})
 }
+
+func TestAccSafeProjectResourceDisappears(t *testing.T) {
+	t.Parallel()
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Delete the project outside of Terraform, the refresh should remove it from the state
+				Config: providerConfig + readTestFile(t, "../../testdata/project/with_location.tf"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("dt_project.test", "name"),
+					testAccCheckResourceDisappears("dt_project.test", (*dt.Client).DeleteProject),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
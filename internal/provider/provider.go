@@ -4,21 +4,48 @@
 package provider
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/cassette"
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/redact"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+const (
+	defaultURL           = "https://api.disruptive-technologies.com"
+	defaultEmulatorURL   = "https://emulator.disruptive-technologies.com/"
+	defaultTokenEndpoint = "https://identity.disruptive-technologies.com/oauth2/token"
+	// defaultRequestTimeout is the timeout of each HTTP request when transport.request_timeout is not set.
+	defaultRequestTimeout = time.Minute
+)
+
+// credentialEnvVars are the environment variables of the service account key attributes.
+var credentialEnvVars = map[string]string{
+	"key_id":     "DT_API_KEY_ID",
+	"key_secret": "DT_API_KEY_SECRET",
+	"email":      "DT_OIDC_EMAIL",
+}
+
 // Ensure The provider satisfies various provider interfaces.
 var _ provider.Provider = &DTProvider{}
 var _ provider.ProviderWithFunctions = &DTProvider{}
@@ -29,6 +56,15 @@ type DTProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// The client created by Configure, and where to write its usage report.
+	mu              sync.Mutex
+	client          *dt.Client
+	usageReportFile string
+
+	// cassette records or replays the requests of the client in acceptance
+	// tests, see testAccProviderFactories. It is nil otherwise.
+	cassette *cassette.Cassette
 }
 
 // DTProviderModel describes the provider data model.
@@ -43,6 +79,10 @@ func (p *DTProvider) Metadata(ctx context.Context, req provider.MetadataRequest,
 
 func (p *DTProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "The service account key, `key_id`, `key_secret` and `email`, must be set in the configuration, " +
+			"with the `DT_API_KEY_ID`, `DT_API_KEY_SECRET` and `DT_OIDC_EMAIL` environment variables, or in a credentials file. " +
+			"The provider fails to configure with a `Missing service account credentials` error for each of them that is not set. " +
+			"This is a breaking change in 0.1.0: earlier versions configured the provider with an empty key, and only failed when they sent a request.",
 		Attributes: map[string]schema.Attribute{
 			"url": schema.StringAttribute{
 				Description: "The URL of the API server.",
@@ -55,12 +95,12 @@ func (p *DTProvider) Schema(ctx context.Context, req provider.SchemaRequest, res
 				Optional: true,
 			},
 			"key_id": schema.StringAttribute{
-				Description: "The key ID from the service account.",
+				Description: "The key ID from the service account. Required unless it is set with the `DT_API_KEY_ID` environment variable or in the credentials file.",
 				// Can use either environment variables or configuration, therefore optional: true
 				Optional: true,
 			},
 			"key_secret": schema.StringAttribute{
-				Description: "The key secret from the service account.",
+				Description: "The key secret from the service account. Required unless it is set with the `DT_API_KEY_SECRET` environment variable or in the credentials file.",
 				Sensitive:   true,
 				// Can use either environment variables or configuration, therefore optional: true
 				Optional: true,
@@ -71,10 +111,155 @@ func (p *DTProvider) Schema(ctx context.Context, req provider.SchemaRequest, res
 				Optional: true,
 			},
 			"email": schema.StringAttribute{
-				Description: "The email address used to authenticate with the OIDC provider.",
+				Description: "The email address used to authenticate with the OIDC provider. Required unless it is set with the `DT_OIDC_EMAIL` environment variable or in the credentials file.",
 				// Can use either environment variables or configuration, therefore optional: true
 				Optional: true,
 			},
+			"credentials_file": schema.StringAttribute{
+				Description: "Path to a JSON file with the service account key, such as a key downloaded from DT Studio, and optionally the `url`, `emulator_url` and `token_endpoint` to use with it. " +
+					"The file can also hold several named profiles, see `profile`. Can also be set with the `DT_CREDENTIALS_FILE` environment variable. " +
+					"Values from the file are only used for attributes that are not set in the configuration or by their environment variables.",
+				Optional: true,
+			},
+			"profile": schema.StringAttribute{
+				Description: "Name of the profile to use in the credentials file. Defaults to `default` for files with profiles. " +
+					"If `credentials_file` is not set, the profile is read from `dt/credentials.json` in the user configuration directory, " +
+					"such as `~/.config/dt/credentials.json` on Linux. Can also be set with the `DT_PROFILE` environment variable.",
+				Optional: true,
+			},
+			"token_cache_dir": schema.StringAttribute{
+				Description: "Directory of an on-disk cache of access tokens, such as `pathexpand(\"~/.cache/dt/tokens\")`. With the cache, the provider processes that Terraform starts " +
+					"for validate, plan and apply share a token for each service account key and token endpoint, instead of each requesting one. " +
+					"The cached tokens are only readable by their owner. Disabled by default. Can also be set with the `DT_TOKEN_CACHE_DIR` environment variable.",
+				Optional: true,
+			},
+			"token_refresh_margin": schema.StringAttribute{
+				Description: "How long before they expire access tokens are refreshed, as a duration such as `5m`. " +
+					"Refreshing early keeps tokens from expiring during requests, or early because of clock skew. Defaults to `5m`.",
+				Optional: true,
+			},
+			"token_timeout": schema.StringAttribute{
+				Description: "The timeout of requests to the token endpoint, as a duration such as `10s`. Defaults to `10s`.",
+				Optional:    true,
+			},
+			"usage_report_file": schema.StringAttribute{
+				Description: "Path of a file that a JSON report of the requests sent to the API is written to when the provider exits, " +
+					"with the number of requests per endpoint, rate limited requests, token fetches and cache hit rates. " +
+					"Terraform starts a provider process for each validate, plan and apply, and for each provider alias, so the reports of the processes of a Terraform run are summed up, " +
+					"with the number of processes in `processes` and the process ID of Terraform in `terraform_pid`. The report of an earlier run is replaced. " +
+					"The report of each process is also written to the logs at the INFO level. Can also be set with the `DT_USAGE_REPORT_FILE` environment variable.",
+				Optional: true,
+			},
+			"cache": schema.SingleNestedAttribute{
+				Description: "How projects and notification rules are cached. The provider reads every project in an organization, and every rule in a project, with a single request and caches them for the rest of the run.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether to cache projects and notification rules. Disable the cache to read every resource with a separate request. Defaults to true.",
+						Optional:    true,
+					},
+					"ttl": schema.StringAttribute{
+						Description: "How long cached resources are used before they are read again, as a duration such as `5m`. By default cached resources do not expire during a run.",
+						Optional:    true,
+					},
+				},
+			},
+			"rate_limit": schema.SingleNestedAttribute{
+				Description: "How fast requests are sent to the API. The requests of all resources share a token bucket rate limiter and a cap on the requests in flight, " +
+					"so that large applies send requests at a steady rate instead of in bursts that the API rate limits. " +
+					"The rate is halved when the API rate limits a request, recovers as requests succeed, and follows the rate limit headers of the API.",
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether to limit the rate of requests. Defaults to true.",
+						Optional:    true,
+					},
+					"requests_per_second": schema.Float64Attribute{
+						Description: "The sustained rate of requests. Defaults to 20.",
+						Optional:    true,
+						Validators: []validator.Float64{
+							float64validator.AtLeast(0.1),
+						},
+					},
+					"burst": schema.Int64Attribute{
+						Description: "The number of requests that can be sent at once after a quiet period. Defaults to 20.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"max_in_flight": schema.Int64Attribute{
+						Description: "The maximum number of requests sent at the same time. Defaults to 10, the default parallelism of Terraform.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+			"retry": schema.SingleNestedAttribute{
+				Description: "How requests to the API are retried when they fail with a transient error.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"max_attempts": schema.Int64Attribute{
+						Description: "The maximum number of attempts for each request, including the first one. Defaults to 5.",
+						Optional:    true,
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"min_backoff": schema.StringAttribute{
+						Description: "The wait before the first retry, as a duration such as `500ms`. The wait doubles for every following retry. Defaults to `500ms`.",
+						Optional:    true,
+					},
+					"max_backoff": schema.StringAttribute{
+						Description: "The longest wait between two attempts, as a duration such as `30s`. Defaults to `30s`.",
+						Optional:    true,
+					},
+					"retryable_status_codes": schema.ListAttribute{
+						Description: "The HTTP status codes that are retried. Defaults to 429, 502, 503 and 504.",
+						ElementType: types.Int64Type,
+						Optional:    true,
+					},
+					"retry_non_idempotent": schema.BoolAttribute{
+						Description: "Also retry requests that are not idempotent, such as the requests that create resources. These are otherwise only retried when rate limited, since the API might already have processed them. Defaults to false.",
+						Optional:    true,
+					},
+				},
+			},
+			"transport": schema.SingleNestedAttribute{
+				Description: "How the provider connects to the API and the token endpoint, for networks that require a proxy or TLS inspection.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"proxy_url": schema.StringAttribute{
+						Description: "The URL of the proxy to send requests through, such as `http://proxy.example.com:3128`. " +
+							"Defaults to the proxy set by the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables.",
+						Optional: true,
+					},
+					"ca_bundle": schema.StringAttribute{
+						Description: "PEM encoded CA certificates, or the path to a file with them, that are trusted in addition to the system certificates. " +
+							"Use it to trust the certificate of a TLS inspecting proxy.",
+						Optional: true,
+					},
+					"client_certificate": schema.StringAttribute{
+						Description: "A PEM encoded client certificate, or the path to a file with it, for proxies that require mutual TLS. Requires `client_key`.",
+						Optional:    true,
+					},
+					"client_key": schema.StringAttribute{
+						Description: "The PEM encoded private key of `client_certificate`, or the path to a file with it.",
+						Optional:    true,
+						Sensitive:   true,
+					},
+					"request_timeout": schema.StringAttribute{
+						Description: "The timeout of each HTTP request, as a duration such as `30s`. Defaults to `1m`.",
+						Optional:    true,
+					},
+					"timeout": schema.StringAttribute{
+						Description: "The longest time spent on a request to the API, including retries, as a duration such as `5m`. By default there is no limit.",
+						Optional:    true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -88,6 +273,173 @@ type dtProviderModel struct {
 	ClientSecret  types.String `tfsdk:"key_secret"`
 	TokenEndpoint types.String `tfsdk:"token_endpoint"`
 	Email         types.String `tfsdk:"email"`
+	// Credentials file
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+	Profile         types.String `tfsdk:"profile"`
+	// Tokens
+	TokenCacheDir      types.String `tfsdk:"token_cache_dir"`
+	TokenRefreshMargin types.String `tfsdk:"token_refresh_margin"`
+	TokenTimeout       types.String `tfsdk:"token_timeout"`
+
+	UsageReportFile types.String `tfsdk:"usage_report_file"`
+
+	Cache     *cacheModel     `tfsdk:"cache"`
+	RateLimit *rateLimitModel `tfsdk:"rate_limit"`
+	Retry     *retryModel     `tfsdk:"retry"`
+	Transport *transportModel `tfsdk:"transport"`
+}
+
+// cacheModel maps the cache block of the provider schema to a Go type.
+type cacheModel struct {
+	Enabled types.Bool   `tfsdk:"enabled"`
+	TTL     types.String `tfsdk:"ttl"`
+}
+
+// cacheConfig converts the cache block to a dt.CacheConfig.
+func (m *cacheModel) cacheConfig() (dt.CacheConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var cfg dt.CacheConfig
+	if m == nil {
+		return cfg, diags
+	}
+	cfg.Disabled = !m.Enabled.IsNull() && !m.Enabled.ValueBool()
+	cfg.TTL = parseDuration(path.Root("cache").AtName("ttl"), m.TTL, &diags)
+	return cfg, diags
+}
+
+// rateLimitModel maps the rate_limit block of the provider schema to a Go type.
+type rateLimitModel struct {
+	Enabled           types.Bool    `tfsdk:"enabled"`
+	RequestsPerSecond types.Float64 `tfsdk:"requests_per_second"`
+	Burst             types.Int64   `tfsdk:"burst"`
+	MaxInFlight       types.Int64   `tfsdk:"max_in_flight"`
+}
+
+// rateLimit converts the rate_limit block to a dt.RateLimit. Unset attributes
+// are left as zero values, so that the client uses its defaults.
+func (m *rateLimitModel) rateLimit() dt.RateLimit {
+	var rateLimit dt.RateLimit
+	if m == nil {
+		return rateLimit
+	}
+	rateLimit.Disabled = !m.Enabled.IsNull() && !m.Enabled.ValueBool()
+	rateLimit.RequestsPerSecond = m.RequestsPerSecond.ValueFloat64()
+	rateLimit.Burst = int(m.Burst.ValueInt64())
+	rateLimit.MaxInFlight = int(m.MaxInFlight.ValueInt64())
+	return rateLimit
+}
+
+// retryModel maps the retry block of the provider schema to a Go type.
+type retryModel struct {
+	MaxAttempts          types.Int64  `tfsdk:"max_attempts"`
+	MinBackoff           types.String `tfsdk:"min_backoff"`
+	MaxBackoff           types.String `tfsdk:"max_backoff"`
+	RetryableStatusCodes types.List   `tfsdk:"retryable_status_codes"`
+	RetryNonIdempotent   types.Bool   `tfsdk:"retry_non_idempotent"`
+}
+
+// retryPolicy converts the retry block to a dt.RetryPolicy. Unset attributes
+// are left as zero values, so that the client uses its defaults.
+func (m *retryModel) retryPolicy(ctx context.Context) (dt.RetryPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var policy dt.RetryPolicy
+	if m == nil {
+		return policy, diags
+	}
+
+	policy.MaxAttempts = int(m.MaxAttempts.ValueInt64())
+	policy.RetryNonIdempotent = m.RetryNonIdempotent.ValueBool()
+
+	policy.MinBackoff = parseDuration(path.Root("retry").AtName("min_backoff"), m.MinBackoff, &diags)
+	policy.MaxBackoff = parseDuration(path.Root("retry").AtName("max_backoff"), m.MaxBackoff, &diags)
+
+	if !m.RetryableStatusCodes.IsNull() && !m.RetryableStatusCodes.IsUnknown() {
+		var statusCodes []int64
+		diags.Append(m.RetryableStatusCodes.ElementsAs(ctx, &statusCodes, false)...)
+		policy.RetryableStatusCodes = make([]int, 0, len(statusCodes))
+		for _, statusCode := range statusCodes {
+			policy.RetryableStatusCodes = append(policy.RetryableStatusCodes, int(statusCode))
+		}
+	}
+	return policy, diags
+}
+
+// transportModel maps the transport block of the provider schema to a Go type.
+type transportModel struct {
+	ProxyURL          types.String `tfsdk:"proxy_url"`
+	CABundle          types.String `tfsdk:"ca_bundle"`
+	ClientCertificate types.String `tfsdk:"client_certificate"`
+	ClientKey         types.String `tfsdk:"client_key"`
+	RequestTimeout    types.String `tfsdk:"request_timeout"`
+	Timeout           types.String `tfsdk:"timeout"`
+}
+
+// transportConfig converts the transport block to a dt.TransportConfig and
+// the timeout of requests to the API including retries.
+func (m *transportModel) transportConfig() (dt.TransportConfig, time.Duration, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	cfg := dt.TransportConfig{RequestTimeout: defaultRequestTimeout}
+	if m == nil {
+		return cfg, 0, diags
+	}
+
+	transport := path.Root("transport")
+	cfg.ProxyURL = m.ProxyURL.ValueString()
+	cfg.CACertificates = readPEM(transport.AtName("ca_bundle"), m.CABundle, &diags)
+	cfg.ClientCertificate = readPEM(transport.AtName("client_certificate"), m.ClientCertificate, &diags)
+	cfg.ClientKey = readPEM(transport.AtName("client_key"), m.ClientKey, &diags)
+	if (len(cfg.ClientCertificate) > 0) != (len(cfg.ClientKey) > 0) {
+		diags.AddAttributeError(
+			transport.AtName("client_certificate"),
+			"Incomplete client certificate",
+			"Both client_certificate and client_key must be set to authenticate with a client certificate.",
+		)
+	}
+	if requestTimeout := parseDuration(transport.AtName("request_timeout"), m.RequestTimeout, &diags); requestTimeout > 0 {
+		cfg.RequestTimeout = requestTimeout
+	}
+	timeout := parseDuration(transport.AtName("timeout"), m.Timeout, &diags)
+	return cfg, timeout, diags
+}
+
+// readPEM returns the PEM encoded value of an attribute, which holds either
+// PEM data or the path to a file with it. It returns nil if the attribute is
+// not set, and adds an attribute error if the file cannot be read.
+func readPEM(attributePath path.Path, value types.String, diags *diag.Diagnostics) []byte {
+	if value.ValueString() == "" {
+		return nil
+	}
+	if strings.Contains(value.ValueString(), "-----BEGIN") {
+		return []byte(value.ValueString())
+	}
+	content, err := os.ReadFile(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(
+			attributePath,
+			"Failed to read PEM file",
+			err.Error(),
+		)
+		return nil
+	}
+	return content
+}
+
+// parseDuration parses a duration attribute such as "30s". It returns zero if
+// the attribute is not set, and adds an attribute error if it is invalid.
+func parseDuration(attributePath path.Path, value types.String, diags *diag.Diagnostics) time.Duration {
+	if value.ValueString() == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(value.ValueString())
+	if err != nil || duration <= 0 {
+		diags.AddAttributeError(
+			attributePath,
+			"Invalid duration",
+			fmt.Sprintf("Expected a positive duration such as 500ms or 30s, got %q", value.ValueString()),
+		)
+		return 0
+	}
+	return duration
 }
 
 func (p *DTProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
@@ -114,7 +466,7 @@ func (p *DTProvider) Configure(ctx context.Context, req provider.ConfigureReques
 	emulatorURL := os.Getenv("DT_EMULATOR_URL")
 	if emulatorURL == "" {
 		if config.EmulatorURL.IsUnknown() {
-			emulatorURL = "https://emulator.disruptive-technologies.com/"
+			emulatorURL = defaultEmulatorURL
 		} else {
 			emulatorURL = config.EmulatorURL.ValueString()
 		}
@@ -170,6 +522,90 @@ func (p *DTProvider) Configure(ctx context.Context, req provider.ConfigureReques
 		}
 	}
 
+	// Values that are not set by environment variables or attributes are read
+	// from the credentials file, and then fall back to the defaults.
+	credentialsFile := os.Getenv("DT_CREDENTIALS_FILE")
+	if credentialsFile == "" {
+		credentialsFile = config.CredentialsFile.ValueString()
+	}
+	profile := os.Getenv("DT_PROFILE")
+	if profile == "" {
+		profile = config.Profile.ValueString()
+	}
+	if credentialsFile != "" || profile != "" {
+		creds, err := loadCredentials(credentialsFile, profile)
+		if err != nil {
+			attribute := path.Root("credentials_file")
+			if credentialsFile == "" {
+				attribute = path.Root("profile")
+			}
+			resp.Diagnostics.AddAttributeError(
+				attribute,
+				"Failed to read credentials file",
+				err.Error(),
+			)
+		}
+		url = cmp.Or(url, creds.URL)
+		emulatorURL = cmp.Or(emulatorURL, creds.EmulatorURL)
+		keyID = cmp.Or(keyID, creds.KeyID)
+		keySecret = cmp.Or(keySecret, creds.KeySecret)
+		tokenEndpoint = cmp.Or(tokenEndpoint, creds.TokenEndpoint)
+		email = cmp.Or(email, creds.Email)
+	}
+	url = cmp.Or(url, defaultURL)
+	emulatorURL = cmp.Or(emulatorURL, defaultEmulatorURL)
+	tokenEndpoint = cmp.Or(tokenEndpoint, defaultTokenEndpoint)
+
+	// Report missing credentials, unless the credentials file could not be read.
+	if !resp.Diagnostics.HasError() {
+		for _, credential := range []struct{ attribute, value string }{
+			{"key_id", keyID},
+			{"key_secret", keySecret},
+			{"email", email},
+		} {
+			if credential.value != "" {
+				continue
+			}
+			resp.Diagnostics.AddAttributeError(
+				path.Root(credential.attribute),
+				"Missing service account credentials",
+				fmt.Sprintf("The %s attribute must be set, either in the configuration, with the %s environment variable, "+
+					"or in the credentials file selected by credentials_file or profile.",
+					credential.attribute, credentialEnvVars[credential.attribute]),
+			)
+		}
+	}
+
+	tokenCacheDir := os.Getenv("DT_TOKEN_CACHE_DIR")
+	if tokenCacheDir == "" {
+		tokenCacheDir = config.TokenCacheDir.ValueString()
+	}
+
+	tokenRefreshMargin := parseDuration(path.Root("token_refresh_margin"), config.TokenRefreshMargin, &resp.Diagnostics)
+	tokenTimeout := parseDuration(path.Root("token_timeout"), config.TokenTimeout, &resp.Diagnostics)
+
+	retryPolicy, diags := config.Retry.retryPolicy(ctx)
+	resp.Diagnostics.Append(diags...)
+	cacheConfig, diags := config.Cache.cacheConfig()
+	resp.Diagnostics.Append(diags...)
+	transportConfig, timeout, diags := config.Transport.transportConfig()
+	resp.Diagnostics.Append(diags...)
+	var httpClient *http.Client
+	if !diags.HasError() {
+		var err error
+		httpClient, err = dt.NewHTTPClient(transportConfig)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("transport"),
+				"Invalid transport configuration",
+				err.Error(),
+			)
+		}
+	}
+	if p.cassette != nil && httpClient != nil {
+		httpClient.Transport = p.cassette.Transport(httpClient.Transport)
+	}
+
 	// if there are any errors, return early
 	if resp.Diagnostics.HasError() {
 		for _, diag := range resp.Diagnostics {
@@ -178,6 +614,18 @@ func (p *DTProvider) Configure(ctx context.Context, req provider.ConfigureReques
 		return
 	}
 
+	// Secrets are masked in the debug logs unless DT_UNSAFE_LOGGING is set,
+	// which is only meant for debugging locally.
+	unsafeLogging := os.Getenv("DT_UNSAFE_LOGGING") != ""
+	if unsafeLogging {
+		resp.Diagnostics.AddWarning(
+			"Unsafe logging enabled",
+			"DT_UNSAFE_LOGGING is set, so secrets such as access tokens and webhook signature secrets are written to the debug logs. "+
+				"Only use it for debugging locally, and never share the logs.",
+		)
+	}
+	ctx = redact.Context(ctx, unsafeLogging, keySecret)
+
 	ctx = tflog.SetField(ctx, "url", url)
 	ctx = tflog.SetField(ctx, "key_id", keyID)
 	ctx = tflog.SetField(ctx, "token_endpoint", tokenEndpoint)
@@ -185,17 +633,36 @@ func (p *DTProvider) Configure(ctx context.Context, req provider.ConfigureReques
 	tflog.Debug(ctx, "provider parameters")
 
 	client := dt.NewClient(dt.Config{
-		URL:         url,
-		EmulatorURL: emulatorURL,
-		Version:     p.version,
+		URL:           url,
+		EmulatorURL:   emulatorURL,
+		Version:       p.version,
+		Retry:         retryPolicy,
+		RateLimit:     config.RateLimit.rateLimit(),
+		Cache:         cacheConfig,
+		UnsafeLogging: unsafeLogging,
+		HTTPClient:    httpClient,
+		Timeout:       timeout,
 		Oidc: oidc.Config{
 			TokenEndpoint: tokenEndpoint,
 			ClientID:      keyID,
 			ClientSecret:  keySecret,
 			Email:         email,
+			UnsafeLogging: unsafeLogging,
+			TokenCacheDir: tokenCacheDir,
+			RefreshMargin: tokenRefreshMargin,
+			Timeout:       tokenTimeout,
 		},
 	})
 
+	usageReportFile := os.Getenv("DT_USAGE_REPORT_FILE")
+	if usageReportFile == "" {
+		usageReportFile = config.UsageReportFile.ValueString()
+	}
+	p.mu.Lock()
+	p.client = client
+	p.usageReportFile = usageReportFile
+	p.mu.Unlock()
+
 	// make the client available to the rest of the provider
 	resp.DataSourceData = client
 	resp.ResourceData = client
@@ -208,6 +675,10 @@ func (p *DTProvider) Resources(_ context.Context) []func() resource.Resource {
 		NewDataConnectorResource,
 		NewNotificationRuleResource,
 		NewEmulatorResource,
+		NewEmulatorEventResource,
+		NewDeviceLabelsResource,
+		NewDeviceTransferResource,
+		NewDeviceClaimResource,
 		NewMemberResource,
 		NewContactGroupResource,
 		NewContactResource,
@@ -218,6 +689,8 @@ func (p *DTProvider) DataSources(ctx context.Context) []func() datasource.DataSo
 	return []func() datasource.DataSource{
 		NewProjectDataSource,
 		NewDeviceDataSource,
+		NewDevicesDataSource,
+		NewDeviceEventsDataSource,
 	}
 }
 
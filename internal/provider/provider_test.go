@@ -3,11 +3,26 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/cassette"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/dtfake"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt/oidc"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/tracing"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"go.opentelemetry.io/otel"
 )
 
 var (
@@ -24,10 +39,186 @@ var (
 	// CLI command executed to create a provider server to which the CLI can
 	// reattach.
 	testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-		"dt": providerserver.NewProtocol6WithError(New("test")()),
+		// The provider is served through the tracing server like the released binary.
+		"dt": func() (tfprotov6.ProviderServer, error) {
+			return tracing.NewServer(providerserver.NewProtocol6(New("test")())(), otel.GetTracerProvider()), nil
+		},
 	}
 )
 
+// testAccProviderFactories returns the provider factories for an acceptance
+// test that records its requests to a cassette in testdata/cassettes, or
+// replays them from it, when DT_RECORD_MODE is record or replay. The cassette
+// is named after the test, so call it once per test and share the factories
+// between its test cases. In replay mode, tests without a cassette are skipped.
+func testAccProviderFactories(t *testing.T) map[string]func() (tfprotov6.ProviderServer, error) {
+	t.Helper()
+	mode, err := cassette.ParseMode(os.Getenv("DT_RECORD_MODE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode == cassette.ModeOff {
+		return testAccProtoV6ProviderFactories
+	}
+
+	c, err := cassette.Open(filepath.Join("../../testdata/cassettes", t.Name()+".json"), mode, os.Getenv("DT_API_KEY_SECRET"))
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skipf("no cassette to replay: %v", err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return map[string]func() (tfprotov6.ProviderServer, error){
+		"dt": func() (tfprotov6.ProviderServer, error) {
+			p := &DTProvider{version: "test", cassette: c}
+			return tracing.NewServer(providerserver.NewProtocol6(p)(), otel.GetTracerProvider()), nil
+		},
+	}
+}
+
+// TestMain runs the acceptance tests against an in-process fake of the DT APIs
+// when DT_FAKE_API is set, so that they can run without live credentials.
+func TestMain(m *testing.M) {
+	if os.Getenv("DT_RECORD_MODE") == string(cassette.ModeReplay) {
+		// Replayed requests are never sent, but the provider still needs
+		// credentials to sign the token requests with.
+		for key, value := range map[string]string{
+			"DT_API_KEY_ID":     "replay",
+			"DT_API_KEY_SECRET": "replay",
+			"DT_OIDC_EMAIL":     "replay@example.com",
+		} {
+			if os.Getenv(key) == "" {
+				if err := os.Setenv(key, value); err != nil {
+					panic(err)
+				}
+			}
+		}
+	}
+	if os.Getenv("DT_FAKE_API") == "" {
+		os.Exit(m.Run())
+	}
+
+	server := dtfake.NewServer()
+	seedFakeServer(server)
+
+	// Environment variables take precedence over the provider configuration.
+	for key, value := range map[string]string{
+		"DT_API_URL":             server.URL,
+		"DT_EMULATOR_URL":        server.EmulatorURL,
+		"DT_OIDC_TOKEN_ENDPOINT": server.TokenEndpoint,
+		"DT_API_KEY_ID":          server.KeyID,
+		"DT_API_KEY_SECRET":      server.KeySecret,
+		"DT_OIDC_EMAIL":          server.Email,
+	} {
+		if err := os.Setenv(key, value); err != nil {
+			panic(err)
+		}
+	}
+
+	code := m.Run()
+	server.Close()
+	os.Exit(code)
+}
+
+// seedFakeServer adds the organization, projects and devices that the test
+// fixtures expect to exist in the acceptance test organization.
+func seedFakeServer(server *dtfake.Server) {
+	const organization = "organizations/cvinmt9aq9sc738g6eog"
+	server.AddOrganization(organization, "Terraform Provider Acceptance Test Org")
+
+	for id, displayName := range map[string]string{
+		"cvinutal2ugc73b866v0":  "manual",
+		"d0919uq3tjjs739bf18g":  "notification rules",
+		"d0hj3ndaoups738bc8og":  "members 1",
+		"d0hj3qdaoups738bc8pg":  "members 2",
+		"d0hj3s5aoups738bc8qg":  "members 3",
+		"d0ito5m62hus73ae3lr0":  "emulators",
+		"d18gf79mee4c73bk8lsg":  "existing project",
+		"d2ltu5rp4ab4c73e09jg0": "device labels",
+		"d2m0ivjp4ab4c73e0b1g":  "inventory",
+		"d2m0j2rp4ab4c73e0b2g":  "site",
+		"d2m1a0bp4ab4c73e0c0g":  "claims",
+		"d2n0a0bp4ab4c73e0d00":  "order a",
+		"d2n0a1bp4ab4c73e0d0g":  "order b",
+	} {
+		server.AddProject(dt.Project{
+			Name:         "projects/" + id,
+			DisplayName:  displayName,
+			Organization: organization,
+			Location:     dt.Location{TimeLocation: "UTC"},
+		})
+	}
+
+	server.AddDevice(dt.Device{
+		Name:   "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg",
+		Type:   "temperature",
+		Labels: map[string]string{"name": "manual temperature sensor", "virtual-sensor": ""},
+		Reported: &dt.Reported{
+			NetworkStatus: &dt.NetworkStatus{
+				SignalStrength:   99,
+				RSSI:             -50,
+				UpdateTime:       "2025-03-12T09:21:07.137Z",
+				CloudConnectors:  []dt.NetworkStatusConnector{{ID: "emulated-ccon", SignalStrength: 99, RSSI: -50}},
+				TransmissionMode: "LOW_POWER_STANDARD_MODE",
+			},
+			BatteryStatus: &dt.BatteryStatus{Percentage: 100, UpdateTime: "2025-03-12T09:21:07.137Z"},
+			Temperature:   &dt.Temperature{Value: 21.5, UpdateTime: "2025-03-12T09:21:07.137Z"},
+		},
+	})
+	for _, event := range []dt.Event{
+		{EventType: "temperature", Timestamp: "2025-03-12T09:01:07.137Z", Data: json.RawMessage(`{"temperature":{"value":20.5,"updateTime":"2025-03-12T09:01:07.137Z"}}`)},
+		{EventType: "networkStatus", Timestamp: "2025-03-12T09:21:07.137Z", Data: json.RawMessage(`{"networkStatus":{"signalStrength":99,"rssi":-50,"updateTime":"2025-03-12T09:21:07.137Z"}}`)},
+		{EventType: "temperature", Timestamp: "2025-03-12T09:21:07.137Z", Data: json.RawMessage(`{"temperature":{"value":21.5,"updateTime":"2025-03-12T09:21:07.137Z"}}`)},
+	} {
+		event.TargetName = "projects/cvinutal2ugc73b866v0/devices/emucvio050h6oic7398hljg"
+		server.AddEvent(event)
+	}
+	server.AddDevice(dt.Device{
+		Name:   "projects/d0919uq3tjjs739bf18g/devices/emud091aassh1nc738nel0g",
+		Type:   "ccon",
+		Labels: map[string]string{"name": "signal tower", "virtual-sensor": ""},
+		Reported: &dt.Reported{
+			ConnectionStatus: &dt.ConnectionStatus{Connection: "ETHERNET", Available: []string{"ETHERNET"}, UpdateTime: "2025-05-21T12:03:44.530Z"},
+		},
+	})
+	server.AddDevice(dt.Device{
+		Name:   "projects/d2ltu5rp4ab4c73e09jg0/devices/emud2ltu8rp4ab4c73e09lcg",
+		Type:   "humidity",
+		Labels: map[string]string{"name": "labelled sensor", "virtual-sensor": "", "owner": "facilities"},
+	})
+	// The device IDs sort in the opposite order of the projects, so that the
+	// devices of all projects are not listed by resource name.
+	for project, id := range map[string]string{
+		"d2n0a0bp4ab4c73e0d00": "emuz2n0a2bp4ab4c73e0d10",
+		"d2n0a1bp4ab4c73e0d0g": "emua2n0a3bp4ab4c73e0d1g",
+	} {
+		server.AddDevice(dt.Device{
+			Name:   "projects/" + project + "/devices/" + id,
+			Type:   "touch",
+			Labels: map[string]string{"name": "order sensor", "order-test": ""},
+		})
+	}
+	for _, id := range []string{"emud2m0jfbp4ab4c73e0b30g", "emud2m0jijp4ab4c73e0b3g0"} {
+		server.AddDevice(dt.Device{
+			Name:   "projects/d2m0ivjp4ab4c73e0b1g/devices/" + id,
+			Type:   "temperature",
+			Labels: map[string]string{"name": "inventory sensor", "virtual-sensor": ""},
+		})
+	}
+	for _, id := range []string{
+		"d2m1a4rp4ab4c73e0c1g", "d2m1a5jp4ab4c73e0c20", "d2m1a6bp4ab4c73e0c2g",
+		"d2m1a7rp4ab4c73e0c30", "d2m1a8bp4ab4c73e0c3g", "d2m1a93p4ab4c73e0c40",
+	} {
+		server.AddClaimableDevice(id, "temperature", "102058")
+	}
+	server.AddKit("acc-test-kit", "d2m1a4rp4ab4c73e0c1g", "d2m1a5jp4ab4c73e0c20")
+	server.AddKit("acc-test-reclaim-kit", "d2m1a7rp4ab4c73e0c30", "d2m1a8bp4ab4c73e0c3g")
+	server.AddContactGroup(dt.ContactGroup{
+		Name:        organization + "/contactGroups/d2dkclv9a2cc7390cis0",
+		DisplayName: "Acceptance test contacts",
+	})
+}
+
 // notificationActionExample is a helper for reading the test .tf file
 func readTestFile(t *testing.T, filePath string) string {
 	t.Helper()
@@ -38,3 +229,56 @@ func readTestFile(t *testing.T, filePath string) string {
 	}
 	return string(content)
 }
+
+// testAccClient returns a client configured from the same environment
+// variables as the provider, defaulting to the production endpoints used by
+// providerConfig.
+func testAccClient() *dt.Client {
+	getenv := func(key, fallback string) string {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+		return fallback
+	}
+	return dt.NewClient(dt.Config{
+		URL:         getenv("DT_API_URL", "https://api.disruptive-technologies.com"),
+		EmulatorURL: getenv("DT_EMULATOR_URL", "https://emulator.disruptive-technologies.com"),
+		Version:     "test",
+		Oidc: oidc.Config{
+			TokenEndpoint: getenv("DT_OIDC_TOKEN_ENDPOINT", "https://identity.disruptive-technologies.com/oauth2/token"),
+			ClientID:      os.Getenv("DT_API_KEY_ID"),
+			ClientSecret:  os.Getenv("DT_API_KEY_SECRET"),
+			Email:         os.Getenv("DT_OIDC_EMAIL"),
+		},
+	})
+}
+
+// testAccCheckResourceDisappears deletes the resource outside of Terraform
+// using its name attribute, to simulate drift.
+func testAccCheckResourceDisappears(resourceName string, deleteFunc func(c *dt.Client, ctx context.Context, name string) error) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found in state: %s", resourceName)
+		}
+		return deleteFunc(testAccClient(), context.Background(), rs.Primary.Attributes["name"])
+	}
+}
+
+func TestAccProviderMissingCredentials(t *testing.T) {
+	// Not parallel, as it changes the environment of the provider.
+	for _, key := range []string{"DT_API_KEY_ID", "DT_API_KEY_SECRET", "DT_OIDC_EMAIL", "DT_CREDENTIALS_FILE", "DT_PROFILE"} {
+		t.Setenv(key, "")
+	}
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: providerConfig + `data "dt_project" "test" {
+					name = "projects/cvinutal2ugc73b866v0"
+				}`,
+				ExpectError: regexp.MustCompile("Missing service account credentials"),
+			},
+		},
+	})
+}
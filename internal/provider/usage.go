@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/filelock"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// ReportUsage logs a report of the requests the provider has sent to the DT
+// API, and adds it to the usage report file if it is set. It does
+// nothing if the provider has not been configured. It is called when the
+// provider exits, at the end of a plan or apply.
+func (p *DTProvider) ReportUsage(ctx context.Context) error {
+	p.mu.Lock()
+	client, usageReportFile := p.client, p.usageReportFile
+	p.mu.Unlock()
+	if client == nil {
+		return nil
+	}
+
+	report := client.UsageReport()
+	requests := make([]string, 0, len(report.Requests))
+	for _, e := range report.Requests {
+		requests = append(requests, fmt.Sprintf("%s %s: %d requests, %d errors", e.Method, e.Endpoint, e.Requests, e.Errors))
+	}
+	fields := map[string]interface{}{
+		"total_requests":           report.TotalRequests,
+		"requests":                 requests,
+		"rate_limited":             report.RateLimited,
+		"retry_after_wait_seconds": report.RetryAfterWaitSeconds,
+		"rate_limit_wait_seconds":  report.RateLimitWaitSeconds,
+		"token_fetches":            report.TokenFetches,
+	}
+	for name, cache := range report.Caches {
+		fields["cache_"+name+"_hit_rate"] = cache.HitRate
+	}
+	tflog.Info(ctx, "DT API usage", fields)
+
+	if usageReportFile == "" {
+		return nil
+	}
+	return writeUsageReport(ctx, usageReportFile, os.Getppid(), report)
+}
+
+// usageReportLockTimeout is how long the provider waits for other provider
+// processes to write their usage reports before giving up.
+const usageReportLockTimeout = 10 * time.Second
+
+// usageReport is the format of the usage report file.
+type usageReport struct {
+	// TerraformPID is the process ID of the Terraform run that the report is for.
+	TerraformPID int `json:"terraform_pid"`
+	// Processes is the number of provider processes that the report sums up.
+	Processes int `json:"processes"`
+	dt.UsageReport
+}
+
+// writeUsageReport adds the report of a provider process to the usage report
+// file. Terraform starts a provider process for each validate, plan and apply,
+// and for each provider alias, so the reports of the processes of the same
+// Terraform run are summed up. The file is locked while it is updated, since
+// the processes can exit at the same time. A report of an earlier run is
+// replaced.
+func writeUsageReport(ctx context.Context, path string, terraformPID int, report dt.UsageReport) error {
+	ctx, cancel := context.WithTimeout(ctx, usageReportLockTimeout)
+	defer cancel()
+	unlock, err := filelock.Lock(ctx, path+".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock usage report: %w", err)
+	}
+	defer unlock()
+
+	merged := usageReport{TerraformPID: terraformPID, Processes: 1, UsageReport: report}
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read usage report: %w", err)
+	}
+	var previous usageReport
+	if err == nil && json.Unmarshal(content, &previous) == nil && previous.TerraformPID == terraformPID {
+		merged.Processes += previous.Processes
+		merged.UsageReport = previous.UsageReport.Add(report)
+	}
+
+	content, err = json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode usage report: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write usage report: %w", err)
+	}
+	return nil
+}
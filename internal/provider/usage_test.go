@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/dt"
+)
+
+func readUsageReport(t *testing.T, path string) usageReport {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var report usageReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	return report
+}
+
+func TestWriteUsageReportSumsProcessesOfARun(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "usage.json")
+	ctx := context.Background()
+	process := dt.UsageReport{
+		Requests:      []dt.EndpointUsage{{Method: "GET", Endpoint: "/v2/projects/*", Requests: 2}},
+		TotalRequests: 2,
+		TokenFetches:  1,
+	}
+
+	// The plan and apply processes of a run, and an alias.
+	for range 3 {
+		if err := writeUsageReport(ctx, path, 100, process); err != nil {
+			t.Fatalf("writeUsageReport() error = %v", err)
+		}
+	}
+	got := readUsageReport(t, path)
+	if got.TerraformPID != 100 || got.Processes != 3 || got.TotalRequests != 6 || got.TokenFetches != 3 || got.Requests[0].Requests != 6 {
+		t.Errorf("usage report = %+v, want the sum of 3 processes of run 100", got)
+	}
+
+	// A later run replaces the report.
+	if err := writeUsageReport(ctx, path, 200, process); err != nil {
+		t.Fatalf("writeUsageReport() error = %v", err)
+	}
+	got = readUsageReport(t, path)
+	if got.TerraformPID != 200 || got.Processes != 1 || got.TotalRequests != 2 {
+		t.Errorf("usage report = %+v, want the report of run 200 only", got)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// fileClient is an OTLP client that appends spans to a file in the OTLP JSON
+// file format, with an export request per line, which the OpenTelemetry
+// Collector can read with its otlpjsonfile receiver. Every line is written with
+// a single write to a file opened for appending, so the provider processes of
+// a Terraform run can write to the same file.
+type fileClient struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File
+}
+
+func (c *fileClient) Start(context.Context) error {
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("tracing: failed to open traces file: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.f = f
+	return nil
+}
+
+func (c *fileClient) Stop(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return nil
+	}
+	err := c.f.Close()
+	c.f = nil
+	return err
+}
+
+func (c *fileClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	line, err := protojson.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("tracing: failed to encode spans: %w", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return fmt.Errorf("tracing: traces file %s is closed", c.path)
+	}
+	if _, err := c.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("tracing: failed to write spans: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright (c) HashiCorp, Inc.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the OpenTelemetry tracer of the provider server.
+const tracerName = "github.com/disruptive-technologies/terraform-provider-dt/internal/tracing"
+
+// Attributes of the spans of provider operations.
+const (
+	// attributeType is the type of the resource or data source, such as dt_project.
+	attributeType = attribute.Key("terraform.type")
+	// attributeOperation is the operation, such as create or read.
+	attributeOperation = attribute.Key("terraform.operation")
+	// attributeResourceName is the name of the DT resource, such as projects/{project}.
+	attributeResourceName = attribute.Key("dt.resource.name")
+)
+
+// server wraps a provider server, tracing the operations on resources and
+// data sources. The other requests are passed through as they are.
+type server struct {
+	tfprotov6.ProviderServer
+
+	tracer trace.Tracer
+	// The span context the spans are children of, if it is valid.
+	parent trace.SpanContext
+
+	// The types of the resources and data sources, read from the provider
+	// schema when they are first needed.
+	typesOnce       sync.Once
+	resourceTypes   map[string]tftypes.Type
+	dataSourceTypes map[string]tftypes.Type
+}
+
+// NewServer returns a provider server that traces the operations of s with
+// spans from tracerProvider.
+func NewServer(s tfprotov6.ProviderServer, tracerProvider trace.TracerProvider) tfprotov6.ProviderServer {
+	return &server{
+		ProviderServer: s,
+		tracer:         tracerProvider.Tracer(tracerName),
+		parent:         parentFromEnvironment(),
+	}
+}
+
+// start starts the span of an operation.
+func (s *server) start(ctx context.Context, typeName, operation string) (context.Context, trace.Span) {
+	if s.parent.IsValid() && !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithRemoteSpanContext(ctx, s.parent)
+	}
+	return s.tracer.Start(ctx, typeName+" "+operation, trace.WithAttributes(
+		attributeType.String(typeName),
+		attributeOperation.String(operation),
+	))
+}
+
+// end ends the span of an operation, with the name of the DT resource in state
+// and the errors in diagnostics.
+func end(span trace.Span, typ tftypes.Type, state *tfprotov6.DynamicValue, diagnostics []*tfprotov6.Diagnostic, err error) {
+	if name := nameFromState(typ, state); name != "" {
+		span.SetAttributes(attributeResourceName.String(name))
+	}
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == tfprotov6.DiagnosticSeverityError {
+			span.SetStatus(codes.Error, diagnostic.Summary)
+			span.AddEvent("error", trace.WithAttributes(
+				attribute.String("summary", diagnostic.Summary),
+				attribute.String("detail", diagnostic.Detail),
+			))
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// types reads the types of the resources and data sources from the provider schema.
+func (s *server) types(ctx context.Context) {
+	s.typesOnce.Do(func() {
+		s.resourceTypes = make(map[string]tftypes.Type)
+		s.dataSourceTypes = make(map[string]tftypes.Type)
+		response, err := s.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+		if err != nil || response == nil {
+			return
+		}
+		for name, schema := range response.ResourceSchemas {
+			s.resourceTypes[name] = schema.ValueType()
+		}
+		for name, schema := range response.DataSourceSchemas {
+			s.dataSourceTypes[name] = schema.ValueType()
+		}
+	})
+}
+
+func (s *server) resourceType(ctx context.Context, typeName string) tftypes.Type {
+	s.types(ctx)
+	return s.resourceTypes[typeName]
+}
+
+func (s *server) dataSourceType(ctx context.Context, typeName string) tftypes.Type {
+	s.types(ctx)
+	return s.dataSourceTypes[typeName]
+}
+
+func (s *server) ConfigureProvider(ctx context.Context, req *tfprotov6.ConfigureProviderRequest) (*tfprotov6.ConfigureProviderResponse, error) {
+	ctx, span := s.start(ctx, "provider", "configure")
+	response, err := s.ProviderServer.ConfigureProvider(ctx, req)
+	var diagnostics []*tfprotov6.Diagnostic
+	if response != nil {
+		diagnostics = response.Diagnostics
+	}
+	end(span, nil, nil, diagnostics, err)
+	return response, err
+}
+
+func (s *server) ReadResource(ctx context.Context, req *tfprotov6.ReadResourceRequest) (*tfprotov6.ReadResourceResponse, error) {
+	typ := s.resourceType(ctx, req.TypeName)
+	ctx, span := s.start(ctx, req.TypeName, "read")
+	response, err := s.ProviderServer.ReadResource(ctx, req)
+	var diagnostics []*tfprotov6.Diagnostic
+	if response != nil {
+		diagnostics = response.Diagnostics
+	}
+	end(span, typ, req.CurrentState, diagnostics, err)
+	return response, err
+}
+
+func (s *server) PlanResourceChange(ctx context.Context, req *tfprotov6.PlanResourceChangeRequest) (*tfprotov6.PlanResourceChangeResponse, error) {
+	typ := s.resourceType(ctx, req.TypeName)
+	ctx, span := s.start(ctx, req.TypeName, "plan")
+	response, err := s.ProviderServer.PlanResourceChange(ctx, req)
+	var diagnostics []*tfprotov6.Diagnostic
+	if response != nil {
+		diagnostics = response.Diagnostics
+	}
+	end(span, typ, req.PriorState, diagnostics, err)
+	return response, err
+}
+
+func (s *server) ApplyResourceChange(ctx context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	typ := s.resourceType(ctx, req.TypeName)
+	operation := "update"
+	switch {
+	case isNull(typ, req.PriorState):
+		operation = "create"
+	case isNull(typ, req.PlannedState):
+		operation = "delete"
+	}
+	ctx, span := s.start(ctx, req.TypeName, operation)
+	response, err := s.ProviderServer.ApplyResourceChange(ctx, req)
+	// The name of a created resource is only known after it has been created.
+	state, diagnostics := req.PriorState, []*tfprotov6.Diagnostic(nil)
+	if response != nil {
+		diagnostics = response.Diagnostics
+		if operation != "delete" {
+			state = response.NewState
+		}
+	}
+	end(span, typ, state, diagnostics, err)
+	return response, err
+}
+
+func (s *server) ImportResourceState(ctx context.Context, req *tfprotov6.ImportResourceStateRequest) (*tfprotov6.ImportResourceStateResponse, error) {
+	ctx, span := s.start(ctx, req.TypeName, "import")
+	span.SetAttributes(attributeResourceName.String(req.ID))
+	response, err := s.ProviderServer.ImportResourceState(ctx, req)
+	var diagnostics []*tfprotov6.Diagnostic
+	if response != nil {
+		diagnostics = response.Diagnostics
+	}
+	end(span, nil, nil, diagnostics, err)
+	return response, err
+}
+
+func (s *server) ReadDataSource(ctx context.Context, req *tfprotov6.ReadDataSourceRequest) (*tfprotov6.ReadDataSourceResponse, error) {
+	typ := s.dataSourceType(ctx, req.TypeName)
+	ctx, span := s.start(ctx, req.TypeName, "read")
+	response, err := s.ProviderServer.ReadDataSource(ctx, req)
+	var state *tfprotov6.DynamicValue
+	var diagnostics []*tfprotov6.Diagnostic
+	if response != nil {
+		state, diagnostics = response.State, response.Diagnostics
+	}
+	end(span, typ, state, diagnostics, err)
+	return response, err
+}
+
+// decode decodes a state, and returns false if it cannot be decoded.
+func decode(typ tftypes.Type, state *tfprotov6.DynamicValue) (tftypes.Value, bool) {
+	if typ == nil || state == nil {
+		return tftypes.Value{}, false
+	}
+	value, err := state.Unmarshal(typ)
+	if err != nil {
+		return tftypes.Value{}, false
+	}
+	return value, true
+}
+
+// isNull reports whether a state is null, which it is before a resource is
+// created and after it is deleted.
+func isNull(typ tftypes.Type, state *tfprotov6.DynamicValue) bool {
+	value, ok := decode(typ, state)
+	return ok && value.IsNull()
+}
+
+// nameFromState returns the name attribute of a state, or the id attribute if
+// it has no name. It returns an empty string if neither is known.
+func nameFromState(typ tftypes.Type, state *tfprotov6.DynamicValue) string {
+	value, ok := decode(typ, state)
+	if !ok || !value.IsKnown() || value.IsNull() {
+		return ""
+	}
+	var attributes map[string]tftypes.Value
+	if err := value.As(&attributes); err != nil {
+		return ""
+	}
+	for _, key := range []string{"name", "id"} {
+		attribute, ok := attributes[key]
+		if !ok || !attribute.IsKnown() || attribute.IsNull() {
+			continue
+		}
+		var name string
+		if err := attribute.As(&name); err == nil && name != "" {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/tracing"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var projectType = tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+	"name":         tftypes.String,
+	"display_name": tftypes.String,
+}}
+
+// project returns the state of a dt_project, which is null if name is empty.
+func project(t *testing.T, name string) *tfprotov6.DynamicValue {
+	t.Helper()
+	value := tftypes.NewValue(projectType, nil)
+	if name != "" {
+		value = tftypes.NewValue(projectType, map[string]tftypes.Value{
+			"name":         tftypes.NewValue(tftypes.String, name),
+			"display_name": tftypes.NewValue(tftypes.String, "project"),
+		})
+	}
+	state, err := tfprotov6.NewDynamicValue(projectType, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &state
+}
+
+// fakeServer is a provider server with a dt_project resource, which applies
+// every change as planned, or fails with diagnostics if they are set.
+type fakeServer struct {
+	tfprotov6.ProviderServer
+	diagnostics []*tfprotov6.Diagnostic
+}
+
+func (s *fakeServer) GetProviderSchema(context.Context, *tfprotov6.GetProviderSchemaRequest) (*tfprotov6.GetProviderSchemaResponse, error) {
+	return &tfprotov6.GetProviderSchemaResponse{
+		ResourceSchemas: map[string]*tfprotov6.Schema{
+			"dt_project": {Block: &tfprotov6.SchemaBlock{Attributes: []*tfprotov6.SchemaAttribute{
+				{Name: "name", Type: tftypes.String, Computed: true},
+				{Name: "display_name", Type: tftypes.String, Required: true},
+			}}},
+		},
+	}, nil
+}
+
+func (s *fakeServer) ApplyResourceChange(_ context.Context, req *tfprotov6.ApplyResourceChangeRequest) (*tfprotov6.ApplyResourceChangeResponse, error) {
+	if s.diagnostics != nil {
+		return &tfprotov6.ApplyResourceChangeResponse{NewState: req.PriorState, Diagnostics: s.diagnostics}, nil
+	}
+	return &tfprotov6.ApplyResourceChangeResponse{NewState: req.PlannedState}, nil
+}
+
+func TestServerTracesApplyResourceChange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		prior         string
+		planned       string
+		diagnostics   []*tfprotov6.Diagnostic
+		wantOperation string
+		wantName      string
+		wantStatus    codes.Code
+	}{
+		{name: "create", planned: "projects/new", wantOperation: "create", wantName: "projects/new"},
+		{name: "update", prior: "projects/p", planned: "projects/p", wantOperation: "update", wantName: "projects/p"},
+		{name: "delete", prior: "projects/p", wantOperation: "delete", wantName: "projects/p"},
+		{
+			name:          "failed update",
+			prior:         "projects/p",
+			planned:       "projects/p",
+			diagnostics:   []*tfprotov6.Diagnostic{{Severity: tfprotov6.DiagnosticSeverityError, Summary: "failed to update project"}},
+			wantOperation: "update",
+			wantName:      "projects/p",
+			wantStatus:    codes.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := tracetest.NewSpanRecorder()
+			server := tracing.NewServer(&fakeServer{diagnostics: tt.diagnostics}, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			_, err := server.ApplyResourceChange(context.Background(), &tfprotov6.ApplyResourceChangeRequest{
+				TypeName:     "dt_project",
+				PriorState:   project(t, tt.prior),
+				PlannedState: project(t, tt.planned),
+			})
+			if err != nil {
+				t.Fatalf("ApplyResourceChange() error = %v", err)
+			}
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("ApplyResourceChange() recorded %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if want := "dt_project " + tt.wantOperation; span.Name() != want {
+				t.Errorf("span name = %q, want %q", span.Name(), want)
+			}
+			attributes := make(map[attribute.Key]string)
+			for _, kv := range span.Attributes() {
+				attributes[kv.Key] = kv.Value.AsString()
+			}
+			if got := attributes["terraform.operation"]; got != tt.wantOperation {
+				t.Errorf("attribute terraform.operation = %q, want %q", got, tt.wantOperation)
+			}
+			if got := attributes["dt.resource.name"]; got != tt.wantName {
+				t.Errorf("attribute dt.resource.name = %q, want %q", got, tt.wantName)
+			}
+			if got := span.Status().Code; got != tt.wantStatus {
+				t.Errorf("span status = %v, want %v", got, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+
+// Package tracing exports OpenTelemetry traces of the provider, with a span for
+// every operation Terraform asks the provider to perform on a resource or data
+// source, and spans for the requests to the DT API made by the operation.
+//
+// Tracing is configured with the standard OpenTelemetry environment variables.
+// It is enabled when OTEL_TRACES_EXPORTER is set to otlp, or when an OTLP
+// endpoint is set with OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT. The protocol defaults to http/protobuf
+// and can be set to grpc with OTEL_EXPORTER_OTLP_PROTOCOL or
+// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL. To write the spans to a file instead of
+// sending them to a collector, set DT_TRACES_FILE to its path. If TRACEPARENT
+// is set, the spans are part of the trace it refers to.
+package tracing
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// serviceName is the default service name of the spans of the provider.
+const serviceName = "terraform-provider-dt"
+
+// tracesFileEnv is the environment variable with the path of the file that
+// spans are written to in the OTLP JSON file format.
+const tracesFileEnv = "DT_TRACES_FILE"
+
+// enabled reports whether tracing is enabled by the environment.
+func enabled() bool {
+	switch os.Getenv("OTEL_TRACES_EXPORTER") {
+	case "otlp":
+		return true
+	case "none":
+		return false
+	}
+	return os.Getenv(tracesFileEnv) != "" || os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup registers a global tracer provider that exports spans as configured
+// by the environment, or does nothing if tracing is not enabled. The returned
+// function flushes the spans that have not been exported yet, and must be
+// called before the provider exits.
+func Setup(ctx context.Context, version string) (func(context.Context) error, error) {
+	if !enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES take precedence over the defaults.
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// newExporter returns the exporter selected by the environment: a file
+// exporter if DT_TRACES_FILE is set, and otherwise an OTLP exporter with the
+// protocol in OTEL_EXPORTER_OTLP_TRACES_PROTOCOL or OTEL_EXPORTER_OTLP_PROTOCOL.
+func newExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	if path := os.Getenv(tracesFileEnv); path != "" {
+		exporter, err = otlptrace.New(ctx, &fileClient{path: path})
+	} else {
+		switch protocol := cmp.Or(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"), os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"), "http/protobuf"); protocol {
+		case "http/protobuf":
+			exporter, err = otlptracehttp.New(ctx)
+		case "grpc":
+			exporter, err = otlptracegrpc.New(ctx)
+		default:
+			return nil, fmt.Errorf("tracing: unsupported OTLP protocol: %s", protocol)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// parentFromEnvironment returns the span context in the TRACEPARENT
+// environment variable, which is invalid if it is not set.
+func parentFromEnvironment() trace.SpanContext {
+	carrier := propagation.MapCarrier{"traceparent": os.Getenv("TRACEPARENT")}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	return trace.SpanContextFromContext(ctx)
+}
@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+
+package tracing_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/tracing"
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// setenv sets the tracing environment variables for the test, and clears the
+// ones that are not in env.
+func setenv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{
+		"DT_TRACES_FILE",
+		"OTEL_TRACES_EXPORTER",
+		"OTEL_EXPORTER_OTLP_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_PROTOCOL",
+		"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL",
+		"TRACEPARENT",
+	} {
+		t.Setenv(key, env[key])
+	}
+}
+
+// exportSpan sets up tracing, exports a span named name and shuts down.
+func exportSpan(t *testing.T, name string) {
+	t.Helper()
+	ctx := context.Background()
+	shutdown, err := tracing.Setup(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := otel.Tracer("test").Start(ctx, name)
+	span.End()
+	if err := shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetupHTTP(t *testing.T) {
+	var mu sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type"))
+	}))
+	defer server.Close()
+	setenv(t, map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": server.URL})
+
+	exportSpan(t, "dt_project create")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 || requests[0] != "POST /v1/traces application/x-protobuf" {
+		t.Errorf("requests = %q, want one protobuf export", requests)
+	}
+}
+
+func TestSetupFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	setenv(t, map[string]string{"DT_TRACES_FILE": path})
+
+	// Every provider process appends its spans to the file.
+	exportSpan(t, "dt_project create")
+	exportSpan(t, "dt_project read")
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var request coltracepb.ExportTraceServiceRequest
+		if err := protojson.Unmarshal(scanner.Bytes(), &request); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		for _, resourceSpans := range request.GetResourceSpans() {
+			for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+				for _, span := range scopeSpans.GetSpans() {
+					names = append(names, span.GetName())
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(names, ", "), "dt_project create, dt_project read"; got != want {
+		t.Errorf("spans = %s, want %s", got, want)
+	}
+}
+
+func TestSetupUnsupportedProtocol(t *testing.T) {
+	setenv(t, map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4318",
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "http/json",
+	})
+	_, err := tracing.Setup(context.Background(), "test")
+	if err == nil || !strings.Contains(err.Error(), "unsupported OTLP protocol: http/json") {
+		t.Errorf("err = %v, want unsupported protocol", err)
+	}
+}
+
+func TestSetupDisabled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	setenv(t, map[string]string{"DT_TRACES_FILE": path, "OTEL_TRACES_EXPORTER": "none"})
+
+	if _, err := tracing.Setup(context.Background(), "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("traces file was created with OTEL_TRACES_EXPORTER=none: %v", err)
+	}
+}
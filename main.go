@@ -9,7 +9,13 @@ import (
 	"log"
 
 	"github.com/disruptive-technologies/terraform-provider-dt/internal/provider"
+	"github.com/disruptive-technologies/terraform-provider-dt/internal/tracing"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6/tf6server"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tfsdklog"
+	"go.opentelemetry.io/otel"
 )
 
 var (
@@ -27,13 +33,44 @@ func main() {
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := providerserver.ServeOpts{
-		Address: "registry.terraform.io/disruptive-technologies/dt",
-		Debug:   debug,
+	ctx := context.Background()
+	shutdownTracing, err := tracing.Setup(ctx, version)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	var opts []tf6server.ServeOpt
+	if debug {
+		opts = append(opts, tf6server.WithManagedDebug())
 	}
 
-	err := providerserver.Serve(context.Background(), provider.New(version), opts)
+	// Serve the provider like providerserver.Serve does, with the operations traced.
+	p := provider.New(version)()
+	err = tf6server.Serve(
+		"registry.terraform.io/disruptive-technologies/dt",
+		func() tfprotov6.ProviderServer {
+			return tracing.NewServer(providerserver.NewProtocol6(p)(), otel.GetTracerProvider())
+		},
+		opts...,
+	)
 
+	// Report the API usage of the run with the logger the provider logs with
+	// while it serves requests.
+	logCtx := tfsdklog.NewRootProviderLogger(ctx,
+		tfsdklog.WithStderrFromInit(),
+		tfsdklog.WithLogName("DT"),
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER", "DT"),
+	)
+	if dtProvider, ok := p.(*provider.DTProvider); ok {
+		if reportErr := dtProvider.ReportUsage(logCtx); reportErr != nil {
+			log.Print(reportErr.Error())
+		}
+	}
+
+	// Export the remaining spans before exiting.
+	if shutdownErr := shutdownTracing(ctx); shutdownErr != nil {
+		log.Print(shutdownErr.Error())
+	}
 	if err != nil {
 		log.Fatal(err.Error())
 	}
@@ -0,0 +1,19 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_emulator" "test" {
+  display_name = "Emulated door"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "contact"
+}
+
+resource "dt_emulator_event" "test" {
+  emulator = dt_emulator.test.name
+  contact = {
+    state = "OPEN"
+  }
+}
+
+data "dt_device" "test" {
+  name       = dt_emulator.test.name
+  depends_on = [dt_emulator_event.test]
+}
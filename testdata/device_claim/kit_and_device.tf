@@ -0,0 +1,33 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_device_claim" "test" {
+  project    = "projects/d2m1a0bp4ab4c73e0c0g"
+  kit_ids    = ["acc-test-kit"]
+  device_ids = ["d2m1a6bp4ab4c73e0c2g"]
+}
+
+resource "dt_notification_rule" "test" {
+  display_name = "Claimed sensors offline"
+  project_id   = "d2m1a0bp4ab4c73e0c0g"
+  devices      = dt_device_claim.test.devices
+
+  trigger = {
+    field      = "connectionStatus"
+    connection = "SENSOR_OFFLINE"
+  }
+  escalation_levels = [
+    {
+      display_name = "Escalation Level 1"
+      actions = [
+        {
+          type = "EMAIL"
+          email_config = {
+            body       = "Sensor $name is offline"
+            recipients = ["this.guy@example.com"]
+            subject    = "Sensor offline alert"
+          }
+        }
+      ]
+    }
+  ]
+}
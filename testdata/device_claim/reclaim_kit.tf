@@ -0,0 +1,6 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_device_claim" "first" {
+  project = "projects/d2m1a0bp4ab4c73e0c0g"
+  kit_ids = ["acc-test-reclaim-kit"]
+}
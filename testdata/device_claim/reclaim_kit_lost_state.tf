@@ -0,0 +1,9 @@
+# Copyright (c) HashiCorp, Inc.
+
+# The kit was claimed by an apply whose state was lost, so it is claimed
+# again together with a device that was not.
+resource "dt_device_claim" "second" {
+  project    = "projects/d2m1a0bp4ab4c73e0c0g"
+  kit_ids    = ["acc-test-reclaim-kit"]
+  device_ids = ["d2m1a93p4ab4c73e0c40"]
+}
@@ -0,0 +1,14 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_device_labels" "test" {
+  name = "projects/d2ltu5rp4ab4c73e09jg0/devices/emud2ltu8rp4ab4c73e09lcg"
+  labels = {
+    room  = "freezer"
+    floor = "2"
+  }
+}
+
+data "dt_device" "test" {
+  name       = dt_device_labels.test.name
+  depends_on = [dt_device_labels.test]
+}
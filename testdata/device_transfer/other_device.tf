@@ -0,0 +1,20 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_device_transfer" "test" {
+  organization   = "organizations/cvinmt9aq9sc738g6eog"
+  project        = "projects/d2m0j2rp4ab4c73e0b2g"
+  return_project = "projects/d2m0ivjp4ab4c73e0b1g"
+  devices = [
+    "projects/d2m0ivjp4ab4c73e0b1g/devices/emud2m0jijp4ab4c73e0b3g0",
+  ]
+}
+
+data "dt_devices" "site" {
+  project    = dt_device_transfer.test.project
+  depends_on = [dt_device_transfer.test]
+}
+
+data "dt_devices" "inventory" {
+  project    = dt_device_transfer.test.return_project
+  depends_on = [dt_device_transfer.test]
+}
@@ -0,0 +1,43 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_emulator" "sensor_a" {
+  display_name = "Sensor A"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "temperature"
+}
+
+resource "dt_emulator" "sensor_b" {
+  display_name = "Sensor B"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "motion"
+}
+
+resource "dt_emulator" "test" {
+  display_name     = "Emulated cloud connector"
+  project_id       = "d0ito5m62hus73ae3lr0"
+  type             = "ccon"
+  connection_state = "CELLULAR"
+  sensors          = [dt_emulator.sensor_b.name]
+}
+
+data "dt_device" "test" {
+  name       = dt_emulator.test.name
+  depends_on = [dt_emulator.test]
+}
+
+data "dt_device_events" "sensor_a" {
+  device      = dt_emulator.sensor_a.name
+  event_types = ["networkStatus"]
+  depends_on  = [dt_emulator.test]
+}
+
+data "dt_device_events" "sensor_b" {
+  device      = dt_emulator.sensor_b.name
+  event_types = ["networkStatus"]
+  depends_on  = [dt_emulator.test]
+}
+
+data "dt_device" "sensor_a" {
+  name       = dt_emulator.sensor_a.name
+  depends_on = [dt_emulator.test]
+}
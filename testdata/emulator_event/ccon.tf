@@ -0,0 +1,19 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_emulator" "test" {
+  display_name = "Emulated cloud connector"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "ccon"
+}
+
+resource "dt_emulator_event" "test" {
+  emulator = dt_emulator.test.name
+  connection_status = {
+    connection = "CELLULAR"
+  }
+}
+
+data "dt_device" "test" {
+  name       = dt_emulator.test.name
+  depends_on = [dt_emulator_event.test]
+}
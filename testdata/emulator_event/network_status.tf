@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_emulator" "test" {
+  display_name = "Emulator with network status"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "humidity"
+}
+
+resource "dt_emulator_event" "test" {
+  emulator = dt_emulator.test.name
+  network_status = {
+    signal_strength = 80
+    rssi            = -60
+    cloud_connectors = [
+      {
+        id              = "emulated-ccon"
+        signal_strength = 80
+        rssi            = -60
+      },
+    ]
+  }
+}
+
+data "dt_device" "test" {
+  name       = dt_emulator.test.name
+  depends_on = [dt_emulator_event.test]
+}
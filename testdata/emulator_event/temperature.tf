@@ -0,0 +1,27 @@
+# Copyright (c) HashiCorp, Inc.
+
+resource "dt_emulator" "test" {
+  display_name = "Emulator with events"
+  project_id   = "d0ito5m62hus73ae3lr0"
+  type         = "temperature"
+}
+
+resource "dt_emulator_event" "test" {
+  emulator = dt_emulator.test.name
+  triggers = {
+    reading = "1"
+  }
+  temperature = {
+    value = 21.5
+  }
+}
+
+data "dt_device" "test" {
+  name       = dt_emulator.test.name
+  depends_on = [dt_emulator_event.test]
+}
+
+data "dt_device_events" "test" {
+  device     = dt_emulator.test.name
+  depends_on = [dt_emulator_event.test]
+}